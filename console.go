@@ -0,0 +1,179 @@
+package main
+
+import (
+	"ent"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/golang/freetype/truetype"
+	"github.com/gopxl/pixel"
+	"github.com/gopxl/pixel/imdraw"
+	"github.com/gopxl/pixel/pixelgl"
+	"github.com/gopxl/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+// A screen that shows a world, which console commands can act on.
+type WorldScreen interface {
+	Screen
+	World() *ent.World
+	WorldToScreen(win *pixelgl.Window) pixel.Matrix
+}
+
+// Get the highest screen in the stack that shows a world, if there is one.
+// Commands act on it even when other screens, such as the pause menu, are above it.
+func shownWorldScreen(stack *SceneStack) (WorldScreen, bool) {
+	for i := len(stack.screens) - 1; i >= 0; i-- {
+		if ws, ok := stack.screens[i].(WorldScreen); ok {
+			return ws, true
+		}
+	}
+	return nil, false
+}
+
+func consoleAtlas() *text.Atlas {
+	ttf, err := truetype.Parse(mainFont)
+	if err != nil {
+		panic(err)
+	}
+	face := truetype.NewFace(ttf, &truetype.Options{
+		Size: 16,
+	})
+	return text.NewAtlas(face, text.ASCII)
+}
+
+const consoleMaxLines = 200
+
+func NewConsole() *Console {
+	return &Console{
+		text:       text.New(pixel.ZV, consoleAtlas()),
+		panel:      imdraw.New(nil),
+		historyPos: -1,
+	}
+}
+
+// A drop-down developer console, which pauses the screen below it while open.
+// While the world below has frames to step, see ent.World.StepFrames, the screens below keep updating so they can be watched without closing the console.
+type Console struct {
+	stack      *SceneStack
+	input      string
+	lines      []string
+	history    []string
+	historyPos int
+	text       *text.Text
+	panel      *imdraw.IMDraw
+}
+
+// Open the console over the top of the stack, or close it if it is already open.
+// Does nothing while the stack is transitioning.
+func (c *Console) Toggle(stack *SceneStack) {
+	if stack.Transitioning() {
+		return
+	}
+	if stack.Top() == Screen(c) {
+		stack.Pop()
+		return
+	}
+	c.stack = stack
+	stack.Push(c)
+}
+
+// DrawsBelow implements DrawsBelower.
+func (c *Console) DrawsBelow() bool {
+	return true
+}
+
+// UpdatesBelow implements UpdatesBelower.
+func (c *Console) UpdatesBelow() bool {
+	ws, ok := shownWorldScreen(c.stack)
+	return ok && ws.World().PendingSteps() > 0
+}
+
+// Update implements Screen.
+func (c *Console) Update(win *pixelgl.Window, dt float64) SceneOp {
+	if win.JustPressed(pixelgl.KeyEscape) {
+		return Pop()
+	}
+	for _, r := range win.Typed() {
+		if r != '`' && r != '~' {
+			c.input += string(r)
+		}
+	}
+	if (win.JustPressed(pixelgl.KeyBackspace) || win.Repeated(pixelgl.KeyBackspace)) && len(c.input) > 0 {
+		_, size := utf8.DecodeLastRuneInString(c.input)
+		c.input = c.input[:len(c.input)-size]
+	}
+	if win.JustPressed(pixelgl.KeyUp) && len(c.history) > 0 {
+		if c.historyPos < 0 {
+			c.historyPos = len(c.history)
+		}
+		c.historyPos = max(c.historyPos-1, 0)
+		c.input = c.history[c.historyPos]
+	}
+	if win.JustPressed(pixelgl.KeyDown) && c.historyPos >= 0 {
+		c.historyPos++
+		if c.historyPos >= len(c.history) {
+			c.historyPos = -1
+			c.input = ""
+		} else {
+			c.input = c.history[c.historyPos]
+		}
+	}
+	if win.JustPressed(pixelgl.KeyEnter) {
+		c.execute(win)
+	}
+	return nil
+}
+
+func (c *Console) execute(win *pixelgl.Window) {
+	line := strings.TrimSpace(c.input)
+	c.input = ""
+	c.historyPos = -1
+	if line == "" {
+		return
+	}
+	c.history = append(c.history, line)
+	ctx := ent.CommandContext{}
+	if ws, ok := shownWorldScreen(c.stack); ok {
+		ctx.World = ws.World()
+		ctx.Cursor = ws.WorldToScreen(win).Unproject(win.MousePosition())
+	}
+	out, err := ent.ExecuteCommand(ctx, line)
+	c.print("> " + line)
+	if err != nil {
+		c.print("error: " + err.Error())
+	} else if out != "" {
+		c.print(out)
+	}
+}
+
+func (c *Console) print(s string) {
+	c.lines = append(c.lines, strings.Split(s, "\n")...)
+	if len(c.lines) > consoleMaxLines {
+		c.lines = c.lines[len(c.lines)-consoleMaxLines:]
+	}
+}
+
+// Draw implements Screen.
+func (c *Console) Draw(win *pixelgl.Window) {
+	bounds := win.Bounds()
+	top := bounds.Max.Y
+	bottom := top - bounds.H()*0.4
+	c.panel.Clear()
+	c.panel.Color = pixel.RGB(0, 0, 0).Mul(pixel.Alpha(0.85))
+	c.panel.Push(pixel.V(bounds.Min.X, bottom), pixel.V(bounds.Max.X, top))
+	c.panel.Rectangle(0)
+	c.panel.Draw(win)
+
+	lineHeight := c.text.LineHeight
+	visible := max(int((top-bottom)/lineHeight)-1, 0)
+	shown := c.lines[max(len(c.lines)-visible, 0):]
+	c.text.Clear()
+	c.text.Color = colornames.Lightgray
+	for _, line := range shown {
+		c.text.WriteString(line + "\n")
+	}
+	c.text.Color = colornames.White
+	c.text.WriteString("> " + c.input + "_")
+	c.text.Draw(win, pixel.IM.Moved(pixel.V(bounds.Min.X+8, bottom+8+lineHeight*float64(len(shown)))))
+}
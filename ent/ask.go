@@ -0,0 +1,139 @@
+package ent
+
+// An object that is able to answer queries sent with Ask.
+type Responder interface {
+	// Called to answer a query.
+	// Should return false if the query is not understood.
+	HandleQuery(*World, any) (any, bool)
+}
+
+// Send a query to the target entity and get its reply immediately.
+// Returns false if the target is not in the world, does not answer the query, or replies with something other than an R.
+func Ask[R any](w *World, target EntityUUIDer, query any) (R, bool) {
+	return ask[R](w, w.newTrace(TraceAsk, ""), target.UUID(), query)
+}
+
+// Send a query to all subscribed entities on the bus (or matching wildcard topics) and collect every reply that is an R.
+func AskAll[R any](w *World, b *Bus, query any) []R {
+	return askAll[R](w, w.newTrace(TraceAskAll, busName(b)), w.recipientsOf(b), query)
+}
+
+// Queue a query to be sent to the target entity during the message phase of World.Update.
+// The reply, as it would be returned by Ask, is passed to onReply once the query has been answered.
+func AskLater[R any](w *World, target EntityUUIDer, query any, onReply func(R, bool)) {
+	if w.buffering() {
+		w.commands.record(func(w *World) { AskLater(w, target, query, onReply) })
+		return
+	}
+	id := target.UUID()
+	w.queueMessage(w.newTrace(TraceAsk, ""), pendingQuery[R]{id, query, onReply}, []EntityUUID{id})
+}
+
+// Queue a query to be sent to all entities subscribed to the bus (or matching wildcard topics) right now,
+// during the message phase of World.Update.
+// The replies, as they would be returned by AskAll, are passed to onReplies once the query has been answered.
+func AskAllLater[R any](w *World, b *Bus, query any, onReplies func([]R)) {
+	if w.buffering() {
+		w.commands.record(func(w *World) { AskAllLater(w, b, query, onReplies) })
+		return
+	}
+	recipients := w.recipientsOf(b)
+	w.queueMessage(w.newTrace(TraceAskAll, busName(b)), pendingQueryAll[R]{recipients, query, onReplies}, recipients)
+}
+
+func ask[R any](w *World, trace MessageTrace, target EntityUUID, query any) (R, bool) {
+	w.traceQuery(trace, query, []EntityUUID{target})
+	e, ok := w.WithUUID(target)
+	if !ok {
+		return *new(R), false
+	}
+	return askEntity[R](w, e, query)
+}
+
+func askAll[R any](w *World, trace MessageTrace, recipients []EntityUUID, query any) []R {
+	w.traceQuery(trace, query, recipients)
+	replies := make([]R, 0)
+	for _, id := range recipients {
+		e, ok := w.WithUUID(id)
+		if !ok {
+			continue
+		}
+		if reply, ok := askEntity[R](w, e, query); ok {
+			replies = append(replies, reply)
+		}
+	}
+	return replies
+}
+
+// Record which of the entities will be asked the query, and which were dropped as they are not in the world.
+func (w *World) traceQuery(trace MessageTrace, query any, ids []EntityUUID) {
+	if w.tracer == nil {
+		return
+	}
+	for _, id := range ids {
+		if w.Has(id) {
+			trace.Recipients = append(trace.Recipients, id)
+		} else {
+			trace.Dropped = append(trace.Dropped, id)
+		}
+	}
+	trace.PayloadType = payloadType(query)
+	w.tracer.Trace(trace)
+}
+
+func askEntity[R any](w *World, e Entity, query any) (R, bool) {
+	responder, ok := e.(Responder)
+	if !ok {
+		return *new(R), false
+	}
+	reply, ok := responder.HandleQuery(w, query)
+	if !ok {
+		return *new(R), false
+	}
+	replyR, ok := reply.(R)
+	return replyR, ok
+}
+
+// A query that has been queued to be answered in the message phase.
+type queuedQuery interface {
+	answer(w *World, trace MessageTrace)
+	payload() any
+}
+
+type pendingQuery[R any] struct {
+	target  EntityUUID
+	query   any
+	onReply func(R, bool)
+}
+
+func (q pendingQuery[R]) answer(w *World, trace MessageTrace) {
+	q.onReply(ask[R](w, trace, q.target, q.query))
+}
+
+func (q pendingQuery[R]) payload() any {
+	return q.query
+}
+
+// Queries are delivered with the same priority as the query itself.
+func (q pendingQuery[R]) Priority() int {
+	return messagePriority(q.query)
+}
+
+type pendingQueryAll[R any] struct {
+	recipients []EntityUUID
+	query      any
+	onReplies  func([]R)
+}
+
+func (q pendingQueryAll[R]) answer(w *World, trace MessageTrace) {
+	q.onReplies(askAll[R](w, trace, q.recipients, q.query))
+}
+
+func (q pendingQueryAll[R]) payload() any {
+	return q.query
+}
+
+// Queries are delivered with the same priority as the query itself.
+func (q pendingQueryAll[R]) Priority() int {
+	return messagePriority(q.query)
+}
@@ -0,0 +1,52 @@
+package ent
+
+import (
+	"slices"
+	"testing"
+)
+
+type testResponder struct {
+	CoreEntity
+	value int
+}
+
+func (r *testResponder) HandleQuery(w *World, query any) (any, bool) {
+	if _, ok := query.(testUrgentQuery); ok {
+		return -r.value, true
+	}
+	return r.value, query == "value"
+}
+
+type testUrgentQuery struct{}
+
+func (testUrgentQuery) Priority() int { return 1 }
+
+func TestAskAllLater(t *testing.T) {
+	w := NewWorld()
+	a, b, silent := &testResponder{value: 1}, &testResponder{value: 2}, &testEntity{}
+	w.AddNow(a, b, silent)
+	bus := NewBus()
+	Subscribe(bus, a, silent, b)
+	var replies []int
+	AskAllLater(w, bus, "value", func(r []int) { replies = r })
+	if replies != nil {
+		t.Fatal("query should not be answered before the message phase")
+	}
+	w.Update(nil, 1)
+	if !slices.Equal(replies, []int{1, 2}) {
+		t.Fatalf("got replies %v, want [1 2]", replies)
+	}
+}
+
+func TestQueuedAsksFollowPriority(t *testing.T) {
+	w := NewWorld()
+	r := &testResponder{value: 3}
+	w.AddNow(r)
+	answered := make([]int, 0)
+	AskLater(w, r, "value", func(reply int, ok bool) { answered = append(answered, reply) })
+	AskLater(w, r, testUrgentQuery{}, func(reply int, ok bool) { answered = append(answered, reply) })
+	w.Update(nil, 1)
+	if !slices.Equal(answered, []int{-3, 3}) {
+		t.Fatalf("the prioritised query should be answered first, got %v", answered)
+	}
+}
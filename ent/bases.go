@@ -1,23 +1,26 @@
 package ent
 
 import (
-	"github.com/google/uuid"
 	"github.com/gopxl/pixel"
 	"github.com/gopxl/pixel/pixelgl"
 )
 
 // Compose to provide basic behaviour to implement Entity.
+// The UUID is allocated by the world when the entity is first added or queued to be added, and is zero before then.
+// An entity belongs to one world at a time, so it can only be added to another once it has been removed from the first.
 type CoreEntity struct {
-	uuid EntityUUID
+	uuid  EntityUUID
+	world *worldState
 }
 
 func (e *CoreEntity) UUID() EntityUUID {
-	if e.uuid == "" {
-		e.uuid = EntityUUID(uuid.NewString())
-	}
 	return e.uuid
 }
 
+func (e *CoreEntity) core() *CoreEntity {
+	return e
+}
+
 func (e *CoreEntity) AfterAdd(*World) {}
 
 func (e *CoreEntity) HandleMessage(*World, any) {}
@@ -57,6 +60,64 @@ func (t *WithTransform) SetAngle(a float64) {
 	t.angle = a
 }
 
+// Compose additionally with MinimalEntity to provide a transform that follows the entity's parent (see World.SetParent).
+// Position and Angle are in world space, while LocalPosition and LocalAngle are relative to the parent.
+type WithLocalTransform struct {
+	localPosition pixel.Vec
+	localAngle    float64
+	parent        Transform
+}
+
+func (t *WithLocalTransform) SetParentTransform(parent Transform) {
+	t.parent = parent
+}
+
+func (t *WithLocalTransform) LocalPosition() pixel.Vec {
+	return t.localPosition
+}
+
+func (t *WithLocalTransform) LocalAngle() float64 {
+	return t.localAngle
+}
+
+func (t *WithLocalTransform) SetLocalPosition(p pixel.Vec) {
+	t.localPosition = p
+}
+
+func (t *WithLocalTransform) SetLocalAngle(a float64) {
+	t.localAngle = a
+}
+
+func (t *WithLocalTransform) Position() pixel.Vec {
+	if t.parent == nil {
+		return t.localPosition
+	}
+	return TransMat(t.parent).Project(t.localPosition)
+}
+
+func (t *WithLocalTransform) Angle() float64 {
+	if t.parent == nil {
+		return t.localAngle
+	}
+	return t.parent.Angle() + t.localAngle
+}
+
+func (t *WithLocalTransform) SetPosition(p pixel.Vec) {
+	if t.parent == nil {
+		t.localPosition = p
+		return
+	}
+	t.localPosition = TransMat(t.parent).Unproject(p)
+}
+
+func (t *WithLocalTransform) SetAngle(a float64) {
+	if t.parent == nil {
+		t.localAngle = a
+		return
+	}
+	t.localAngle = a - t.parent.Angle()
+}
+
 // Compose additionally with MinimalEntity to provide basic behaviour to implement PhysicsBody.
 type WithStaticPhysics struct {
 	WithTransform
@@ -10,45 +10,114 @@ func NewBus() *Bus {
 
 type Bus struct {
 	listeners []EntityUUID
+	topic     string
 }
 
 // Causes a message to be sent to all subscribed entities on the bus.
+// If the bus is a world topic, entities subscribed to matching wildcard topics will also receive it.
 func Emit(w *World, b *Bus, d any) {
-	b.listeners = emitHelper(w, d, b.listeners...)
+	if w.buffering() {
+		w.commands.record(func(w *World) { Emit(w, b, d) })
+		return
+	}
+	emitHelper(w, w.newTrace(TraceEmit, busName(b)), d, w.recipientsOf(b)...)
 }
 
 // Causes a message to be sent to the specified entities directly, bypassing any bus but still calling their HandleMessage function.
 func EmitDirectly(w *World, d any, es ...EntityUUIDer) {
-	emitHelper(w, d, es...)
+	if w.buffering() {
+		w.commands.record(func(w *World) { EmitDirectly(w, d, es...) })
+		return
+	}
+	emitHelper(w, w.newTrace(TraceEmitDirectly, ""), d, es...)
+}
+
+// Causes a message to be sent directly to every entity with the given tag.
+func EmitToTag(w *World, tag string, d any) {
+	if w.buffering() {
+		w.commands.record(func(w *World) { EmitToTag(w, tag, d) })
+		return
+	}
+	emitHelper(w, w.newTrace(TraceEmitToTag, tag), d, slices.Collect(w.WithTag(tag))...)
 }
 
-func emitHelper[T EntityUUIDer](w *World, d any, es ...T) []T {
-	newListeners := make([]T, 0, len(es))
+func emitHelper[T EntityUUIDer](w *World, trace MessageTrace, d any, es ...T) {
+	tracing := w.tracer != nil
+	recipients := make([]Entity, 0, len(es))
 	for _, l := range es {
-		// Only keep if the entity is active in the world or it is queued
-		if !w.HasOrQueued(l) {
-			continue
-		}
-		newListeners = append(newListeners, l)
+		id := l.UUID()
 		// If the entity is in the world now, send message immediately,
 		// otherwise, queue it to be sent once the entity is added (so we dont drop signals).
-		e, ok := w.WithUUID(l.UUID())
-		if ok {
-			e.HandleMessage(w, d)
-		} else {
-			w.queuedAddWaitingSignals[l.UUID()] = append(w.queuedAddWaitingSignals[l.UUID()], d)
+		// Drop the message if the entity is neither active in the world nor queued.
+		if e, ok := w.WithUUID(id); ok {
+			recipients = append(recipients, e)
+			if tracing {
+				trace.Recipients = append(trace.Recipients, id)
+			}
+		} else if w.HasOrQueued(id) {
+			w.queuedAddWaitingSignals[id] = append(w.queuedAddWaitingSignals[id], d)
+			if tracing {
+				trace.Waiting = append(trace.Waiting, id)
+			}
+		} else if tracing {
+			trace.Dropped = append(trace.Dropped, id)
+		}
+	}
+	if tracing {
+		trace.PayloadType = payloadType(d)
+		w.tracer.Trace(trace)
+	}
+	if w.profiler != nil {
+		w.profiler.countMessage()
+	}
+	for _, e := range recipients {
+		// An earlier recipient may have removed this one while handling the message
+		if current, ok := w.WithUUID(e.UUID()); !ok || current != e {
+			continue
+		}
+		w.handleMessageAs(e, d)
+	}
+}
+
+// Call the entity's HandleMessage, recording it as the source of anything it emits.
+func (w *World) handleMessageAs(e Entity, d any) {
+	prev := w.activeEntity
+	w.activeEntity = e.UUID()
+	e.HandleMessage(w, d)
+	w.activeEntity = prev
+	if len(w.scripts) > 0 {
+		w.offerMessageToScripts(e.UUID(), d)
+	}
+}
+
+// Get the entities that should receive a message emitted on the bus, each one only once.
+// Listeners that are no longer active in the world or queued are unsubscribed.
+func (w *World) recipientsOf(b *Bus) []EntityUUID {
+	recipients := make([]EntityUUID, 0, len(b.listeners))
+	seen := make(map[EntityUUID]struct{}, len(b.listeners))
+	for _, bus := range append([]*Bus{b}, w.wildcardTopicsMatching(b.topic)...) {
+		for _, id := range bus.listeners {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				recipients = append(recipients, id)
+			}
 		}
+		bus.listeners = slices.DeleteFunc(bus.listeners, func(id EntityUUID) bool {
+			return !w.HasOrQueued(id)
+		})
 	}
-	return newListeners
+	return recipients
 }
 
 // Subscribes the specified entities to the bus.
+// Panics if an entity has not been added to a world yet, as it has no UUID to subscribe.
 func Subscribe(b *Bus, es ...EntityUUIDer) {
 	for _, e := range es {
-		if slices.Contains(b.listeners, e.UUID()) {
+		id := handleOf(e, "subscribe")
+		if slices.Contains(b.listeners, id) {
 			continue
 		}
-		b.listeners = append(b.listeners, e.UUID())
+		b.listeners = append(b.listeners, id)
 	}
 }
 
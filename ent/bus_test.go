@@ -0,0 +1,27 @@
+package ent
+
+import "testing"
+
+type testRemovingListener struct {
+	testListener
+	removes Entity
+}
+
+func (l *testRemovingListener) HandleMessage(w *World, msg any) {
+	l.testListener.HandleMessage(w, msg)
+	w.RemoveNow(l.removes)
+}
+
+func TestEmitSkipsRecipientsRemovedDuringIt(t *testing.T) {
+	w := NewWorld()
+	victim := &testListener{}
+	remover := &testRemovingListener{removes: victim}
+	w.AddNow(remover, victim)
+	bus := w.Topic("rocks")
+	Subscribe(bus, remover, victim)
+	Subscribe(w.Topic("*"), victim)
+	Emit(w, bus, 1)
+	if len(remover.received) != 1 || len(victim.received) != 0 {
+		t.Fatalf("an entity removed by an earlier recipient should not receive the message, got %v and %v", remover.received, victim.received)
+	}
+}
@@ -52,7 +52,11 @@ func checkActiveBodies(a, b ActivePhysicsBody) (Collision, bool) {
 	if !col.collided {
 		return Collision{}, false
 	}
+	return respondActiveBodies(a, b, col), true
+}
 
+// Updates the two active bodies for a collision that has already been detected
+func respondActiveBodies(a, b ActivePhysicsBody, col shapeCollision) Collision {
 	combinedElasticity := 0.5 * (a.Elasticity() + b.Elasticity())
 	origAVel := VelocityAt(a, col.point)
 	origBVel := VelocityAt(b, col.point)
@@ -76,7 +80,7 @@ func checkActiveBodies(a, b ActivePhysicsBody) (Collision, bool) {
 		Other:  b,
 		Normal: col.normal.Scaled(-1),
 		Point:  col.point,
-	}, true
+	}
 }
 
 // Checks for a collision and updates the active body
@@ -85,7 +89,11 @@ func checkActiveAndKinematicBodies(a ActivePhysicsBody, b PhysicsBody) (Collisio
 	if !col.collided {
 		return Collision{}, false
 	}
+	return respondActiveAndKinematicBodies(a, b, col), true
+}
 
+// Updates the active body for a collision that has already been detected
+func respondActiveAndKinematicBodies(a ActivePhysicsBody, b PhysicsBody, col shapeCollision) Collision {
 	combinedElasticity := 0.5 * (a.Elasticity() + b.Elasticity())
 	aSpeed, aLeftover := decomposeAxis(a.Velocity(), col.normal)
 	bSpeed, _ := decomposeAxis(b.Velocity(), col.normal)
@@ -102,24 +110,13 @@ func checkActiveAndKinematicBodies(a ActivePhysicsBody, b PhysicsBody) (Collisio
 		Other:  b,
 		Normal: col.normal.Scaled(-1),
 		Point:  col.point,
-	}, true
+	}
 }
 
 // Perform a collision physics update on the set of bodies.
 // Perform corrections to overlapping objects, and returns collisions to be passed to handlers.
 func StatelessCollisionPhysics(bodies []PhysicsBody) []Collision {
-	// Sort bodies
-	kinematicBodies := make([]PhysicsBody, 0)
-	activeBodies := make([]ActivePhysicsBody, 0)
-	for _, b := range bodies {
-		ab, ok := b.(ActivePhysicsBody)
-		if !ok || !ab.IsPhysicsActive() {
-			kinematicBodies = append(kinematicBodies, b)
-
-		} else {
-			activeBodies = append(activeBodies, ab)
-		}
-	}
+	activeBodies, kinematicBodies := splitBodies(bodies)
 
 	collisions := make([]Collision, 0)
 
@@ -147,3 +144,19 @@ func StatelessCollisionPhysics(bodies []PhysicsBody) []Collision {
 	}
 	return collisions
 }
+
+// Sort bodies into those that are active and those that are not.
+func splitBodies(bodies []PhysicsBody) ([]ActivePhysicsBody, []PhysicsBody) {
+	kinematicBodies := make([]PhysicsBody, 0)
+	activeBodies := make([]ActivePhysicsBody, 0)
+	for _, b := range bodies {
+		ab, ok := b.(ActivePhysicsBody)
+		if !ok || !ab.IsPhysicsActive() {
+			kinematicBodies = append(kinematicBodies, b)
+
+		} else {
+			activeBodies = append(activeBodies, ab)
+		}
+	}
+	return activeBodies, kinematicBodies
+}
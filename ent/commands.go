@@ -0,0 +1,161 @@
+package ent
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gopxl/pixel"
+)
+
+// What a console command is run against.
+type CommandContext struct {
+	// The world the command acts on, which may be nil if no world is being shown.
+	World *World
+	// The world position under the mouse cursor.
+	Cursor pixel.Vec
+}
+
+// A text command that can be run from a developer console.
+type Command struct {
+	Name string
+	// The arguments the command takes, such as "<tag>" or "[x y]".
+	Usage string
+	Help  string
+	// Called with the arguments after the command name, returning the text to show the user.
+	Run func(ctx CommandContext, args []string) (string, error)
+}
+
+var commands = make(map[string]Command)
+
+// Make a command available to ExecuteCommand.
+// Registering a command with the same name as an existing one replaces it.
+func RegisterCommand(c Command) {
+	commands[c.Name] = c
+}
+
+// Get all registered commands, ordered by name.
+func Commands() []Command {
+	all := make([]Command, 0, len(commands))
+	for _, c := range commands {
+		all = append(all, c)
+	}
+	slices.SortFunc(all, func(a, b Command) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return all
+}
+
+// Parse and run a command line, such as `spawn asteroid` or `say "hello there"`.
+func ExecuteCommand(ctx CommandContext, line string) (string, error) {
+	args, err := ParseCommandLine(line)
+	if err != nil {
+		return "", err
+	}
+	if len(args) == 0 {
+		return "", nil
+	}
+	return RunCommand(ctx, args[0], args[1:]...)
+}
+
+// Run the named command with already parsed arguments.
+func RunCommand(ctx CommandContext, name string, args ...string) (string, error) {
+	c, ok := commands[name]
+	if !ok {
+		return "", fmt.Errorf("unknown command %q, try help", name)
+	}
+	return c.Run(ctx, args)
+}
+
+// Split a command line into words on whitespace.
+// Double quotes group words together, and a backslash escapes the next character.
+func ParseCommandLine(line string) ([]string, error) {
+	args := make([]string, 0)
+	var current strings.Builder
+	inWord, inQuotes, escaped := false, false, false
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			inWord, escaped = true, true
+		case r == '"':
+			inWord, inQuotes = true, !inQuotes
+		case !inQuotes && (r == ' ' || r == '\t'):
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			inWord = true
+			current.WriteRune(r)
+		}
+	}
+	if escaped {
+		return nil, errors.New("line ends with an unfinished escape")
+	}
+	if inQuotes {
+		return nil, errors.New("line has an unclosed quote")
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+func init() {
+	RegisterCommand(Command{
+		Name: "help",
+		Help: "List all commands",
+		Run: func(CommandContext, []string) (string, error) {
+			var out strings.Builder
+			for _, c := range Commands() {
+				fmt.Fprintf(&out, "%s - %s\n", strings.TrimSpace(c.Name+" "+c.Usage), c.Help)
+			}
+			return strings.TrimSuffix(out.String(), "\n"), nil
+		},
+	})
+	RegisterCommand(Command{
+		Name:  "timescale",
+		Usage: "[scale]",
+		Help:  "Get or set how fast time passes, where 0 is paused",
+		Run: func(ctx CommandContext, args []string) (string, error) {
+			if ctx.World == nil {
+				return "", ErrNoWorld
+			}
+			if len(args) > 0 {
+				scale, err := strconv.ParseFloat(args[0], 64)
+				if err != nil || scale < 0 {
+					return "", fmt.Errorf("scale must be a number that is not negative, not %q", args[0])
+				}
+				ctx.World.SetTimeScale(scale)
+			}
+			return fmt.Sprintf("time scale is %g", ctx.World.TimeScale()), nil
+		},
+	})
+	RegisterCommand(Command{
+		Name:  "step",
+		Usage: "[frames]",
+		Help:  "Pause, then update for one more frame or the given number",
+		Run: func(ctx CommandContext, args []string) (string, error) {
+			if ctx.World == nil {
+				return "", ErrNoWorld
+			}
+			frames := 1
+			if len(args) > 0 {
+				n, err := strconv.Atoi(args[0])
+				if err != nil || n < 1 {
+					return "", fmt.Errorf("frames must be a positive number, not %q", args[0])
+				}
+				frames = n
+			}
+			ctx.World.Pause()
+			ctx.World.StepFrames(frames)
+			return fmt.Sprintf("stepping %d frames", frames), nil
+		},
+	})
+}
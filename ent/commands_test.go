@@ -0,0 +1,79 @@
+package ent
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseCommandLine(t *testing.T) {
+	for _, tc := range []struct {
+		line string
+		want []string
+		err  bool
+	}{
+		{line: "", want: []string{}},
+		{line: "  spawn   asteroid\t3 ", want: []string{"spawn", "asteroid", "3"}},
+		{line: `say "hello there"`, want: []string{"say", "hello there"}},
+		{line: `say ""`, want: []string{"say", ""}},
+		{line: `say hel"lo th"ere`, want: []string{"say", "hello there"}},
+		{line: `say \"quoted\" a\ b`, want: []string{"say", `"quoted"`, "a b"}},
+		{line: `say "unclosed`, err: true},
+		{line: `say trailing\`, err: true},
+	} {
+		got, err := ParseCommandLine(tc.line)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %q", tc.line, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tc.line, err)
+			continue
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%q: got %q, want %q", tc.line, got, tc.want)
+		}
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	var gotArgs []string
+	RegisterCommand(Command{
+		Name: "test_echo",
+		Run: func(_ CommandContext, args []string) (string, error) {
+			gotArgs = args
+			if len(args) == 0 {
+				return "", errors.New("usage: test_echo <words>")
+			}
+			return strings.Join(args, " "), nil
+		},
+	})
+	defer delete(commands, "test_echo")
+
+	out, err := ExecuteCommand(CommandContext{}, `test_echo "a b" c`)
+	if err != nil || out != "a b c" {
+		t.Fatalf("got %q, %v", out, err)
+	}
+	if !slices.Equal(gotArgs, []string{"a b", "c"}) {
+		t.Fatalf("command was given %q", gotArgs)
+	}
+	if _, err := RunCommand(CommandContext{}, "test_echo"); err == nil {
+		t.Fatal("expected the command's error for missing arguments")
+	}
+	if _, err := RunCommand(CommandContext{}, "no_such_command"); err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Fatalf("expected an unknown command error, got %v", err)
+	}
+	if out, err := ExecuteCommand(CommandContext{}, "   "); err != nil || out != "" {
+		t.Fatalf("an empty line should do nothing, got %q, %v", out, err)
+	}
+	if _, err := ExecuteCommand(CommandContext{}, `test_echo "oops`); err == nil {
+		t.Fatal("expected a parse error")
+	}
+	help, err := RunCommand(CommandContext{}, "help")
+	if err != nil || !strings.Contains(help, "test_echo") {
+		t.Fatalf("help should list registered commands, got %q, %v", help, err)
+	}
+}
@@ -0,0 +1,139 @@
+package ent
+
+import (
+	"math"
+
+	"github.com/gopxl/pixel"
+	"github.com/gopxl/pixel/imdraw"
+	"github.com/gopxl/pixel/pixelgl"
+)
+
+// The kind of a debug drawing primitive.
+type DebugPrimitiveKind int
+
+const (
+	// An outline of a circle at A with the radius.
+	DebugCircle DebugPrimitiveKind = iota
+	// A line from A to B.
+	DebugLine
+	// A filled dot at A with the radius.
+	DebugDot
+)
+
+// A shape to be drawn by a debug overlay, in world space.
+type DebugPrimitive struct {
+	Kind   DebugPrimitiveKind
+	A, B   pixel.Vec
+	Radius float64
+	Color  pixel.RGBA
+}
+
+var (
+	debugActiveColor   = pixel.RGB(0.2, 1, 0.2)
+	debugInactiveColor = pixel.RGB(0.5, 0.5, 0.5)
+	debugAreaColor     = pixel.RGB(0.2, 0.3, 0.8)
+	debugVelocityColor = pixel.RGB(1, 0.9, 0.2)
+	debugContactColor  = pixel.RGB(1, 0.2, 0.2)
+)
+
+// Get the primitives that show what the physics engine thinks of the bodies and collisions.
+// Each body's shape is green when active and grey when not, with its effect area in blue and its velocity in yellow.
+// Each collision's contact point and normal are in red.
+func PhysicsDebugGeometry(bodies []PhysicsBody, collisions []Collision) []DebugPrimitive {
+	prims := make([]DebugPrimitive, 0)
+	for _, body := range bodies {
+		color := debugInactiveColor
+		if ab, ok := body.(ActivePhysicsBody); ok && ab.IsPhysicsActive() {
+			color = debugActiveColor
+		}
+		shape := body.Shape()
+		center, radius := shape.EffectArea()
+		prims = append(prims, DebugPrimitive{Kind: DebugCircle, A: center, Radius: radius, Color: debugAreaColor})
+		prims = appendShapeGeometry(prims, shape, color)
+		if v := body.Velocity(); v != pixel.ZV {
+			prims = append(prims, DebugPrimitive{Kind: DebugLine, A: body.Position(), B: body.Position().Add(v), Color: debugVelocityColor})
+		}
+	}
+	for _, col := range collisions {
+		prims = append(prims,
+			DebugPrimitive{Kind: DebugDot, A: col.Point, Radius: 0.1, Color: debugContactColor},
+			DebugPrimitive{Kind: DebugLine, A: col.Point, B: col.Point.Add(col.Normal), Color: debugContactColor},
+		)
+	}
+	return prims
+}
+
+func appendShapeGeometry(prims []DebugPrimitive, shape Shape, color pixel.RGBA) []DebugPrimitive {
+	switch s := shape.(type) {
+	case Circle:
+		prims = append(prims, DebugPrimitive{Kind: DebugCircle, A: s.Center, Radius: s.Radius, Color: color})
+	case Line:
+		prims = append(prims, DebugPrimitive{Kind: DebugLine, A: s.A, B: s.B, Color: color})
+	case MultiShape:
+		for _, sub := range s.Shapes {
+			prims = appendShapeGeometry(prims, sub, color)
+		}
+	}
+	return prims
+}
+
+// Get the collisions that were found in the last call to Update.
+func (w *World) LastCollisions() []Collision {
+	return w.lastCollisions
+}
+
+// Create an overlay that draws the physics debug geometry of the world it is added to.
+// It starts hidden, call Toggle to show it.
+func NewPhysicsDebugDraw() *PhysicsDebugDraw {
+	return &PhysicsDebugDraw{imd: imdraw.New(nil)}
+}
+
+// An entity that draws PhysicsDebugGeometry on top of everything else.
+type PhysicsDebugDraw struct {
+	CoreEntity
+	WithDraw
+	imd     *imdraw.IMDraw
+	visible bool
+}
+
+// Show the overlay if it is hidden, or hide it if it is shown.
+func (d *PhysicsDebugDraw) Toggle() {
+	d.visible = !d.visible
+}
+
+func (d *PhysicsDebugDraw) Visible() bool {
+	return d.visible
+}
+
+func (d *PhysicsDebugDraw) Draw(win *pixelgl.Window, world *World, worldToScreen pixel.Matrix) {
+	if !d.visible {
+		return
+	}
+	bodies := make([]PhysicsBody, 0)
+	for b := range IndexOf[PhysicsBody](world) {
+		bodies = append(bodies, b)
+	}
+	// Thickness is scaled by the matrix too, so undo that to keep lines a pixel wide
+	scale := worldToScreen.Project(pixel.V(1, 0)).Sub(worldToScreen.Project(pixel.ZV)).Len()
+	d.imd.Clear()
+	d.imd.SetMatrix(worldToScreen)
+	for _, p := range PhysicsDebugGeometry(bodies, world.LastCollisions()) {
+		d.imd.Color = p.Color
+		switch p.Kind {
+		case DebugCircle:
+			d.imd.Push(p.A)
+			d.imd.Circle(p.Radius, 1/scale)
+		case DebugDot:
+			d.imd.Push(p.A)
+			d.imd.Circle(p.Radius, 0)
+		case DebugLine:
+			d.imd.Push(p.A, p.B)
+			d.imd.Line(1 / scale)
+		}
+	}
+	d.imd.Draw(win)
+}
+
+func (d *PhysicsDebugDraw) DrawLayer() int {
+	return math.MinInt
+}
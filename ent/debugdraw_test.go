@@ -0,0 +1,45 @@
+package ent
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gopxl/pixel"
+)
+
+func TestPhysicsDebugGeometry(t *testing.T) {
+	circle := &testBody{radius: 1}
+	circle.SetPosition(pixel.V(1.5, 0))
+	circle.SetVelocity(pixel.V(2, 0))
+	line := &testWall{shape: Line{pixel.V(2, -1), pixel.V(2, 1)}}
+	multi := &testWall{shape: MultiShape{[]Shape{
+		Circle{pixel.V(10, 0), 1},
+		Line{pixel.V(9, 0), pixel.V(11, 0)},
+	}}}
+	bodies := []PhysicsBody{circle, line, multi}
+	collisions := StatelessCollisionPhysics(bodies)
+	if len(collisions) != 1 {
+		t.Fatalf("expected the circle to hit the line once, got %d collisions", len(collisions))
+	}
+
+	want := []DebugPrimitive{
+		// The circle is active, and moving
+		{Kind: DebugCircle, A: circle.Position(), Radius: 1, Color: debugAreaColor},
+		{Kind: DebugCircle, A: circle.Position(), Radius: 1, Color: debugActiveColor},
+		{Kind: DebugLine, A: circle.Position(), B: circle.Position().Add(circle.Velocity()), Color: debugVelocityColor},
+		// The line is kinematic
+		{Kind: DebugCircle, A: pixel.V(2, 0), Radius: 1, Color: debugAreaColor},
+		{Kind: DebugLine, A: pixel.V(2, -1), B: pixel.V(2, 1), Color: debugInactiveColor},
+		// The multi shape shows each of its parts inside one effect area
+		{Kind: DebugCircle, A: pixel.V(10, 0), Radius: pixel.V(2, 2).Len() / 2, Color: debugAreaColor},
+		{Kind: DebugCircle, A: pixel.V(10, 0), Radius: 1, Color: debugInactiveColor},
+		{Kind: DebugLine, A: pixel.V(9, 0), B: pixel.V(11, 0), Color: debugInactiveColor},
+		// The contact point, and the normal pointing from the line back towards the circle
+		{Kind: DebugDot, A: pixel.V(2, 0), Radius: 0.1, Color: debugContactColor},
+		{Kind: DebugLine, A: pixel.V(2, 0), B: pixel.V(1, 0), Color: debugContactColor},
+	}
+	got := PhysicsDebugGeometry(bodies, collisions)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong geometry:\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
@@ -0,0 +1,346 @@
+package ent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gopxl/pixel"
+)
+
+// How long a debug request waits for the game loop to answer it.
+const debugRequestTimeout = 5 * time.Second
+
+// Serves JSON describing a live world over HTTP, for debugging tools.
+// Requests are queued and only answered when Service is called from the game loop, so they never see a half-updated world.
+// The server can also pause and frame-step the world, see World.Pause.
+type DebugServer struct {
+	requests chan debugRequest
+	mux      *http.ServeMux
+	server   *http.Server
+	options  DebugServerOptions
+}
+
+// Optional behaviour for a DebugServer. The zero value serves everything except spawning, and leaves worlds as they are.
+type DebugServerOptions struct {
+	// Spawn count of the named entity at the position for POST /spawn, returning a description of what was spawned.
+	// If nil, POST /spawn is not served.
+	Spawn func(w *World, name string, at pixel.Vec, count int) (string, error)
+	// Give each world the server is serviced with a profiler if it does not have one, so GET /profiler has something to report.
+	Profile bool
+}
+
+type debugRequest struct {
+	handle func(w *World) (any, int)
+	reply  chan debugReply
+}
+
+type debugReply struct {
+	body   any
+	status int
+}
+
+// Create a debug server. Call Listen to serve it, or use Handler directly.
+func NewDebugServer(options DebugServerOptions) *DebugServer {
+	s := &DebugServer{
+		requests: make(chan debugRequest, 64),
+		mux:      http.NewServeMux(),
+		options:  options,
+	}
+	s.mux.HandleFunc("GET /entities", s.onGameLoop(s.getEntities))
+	s.mux.HandleFunc("GET /entities/{id}", s.onGameLoop(s.getEntity))
+	s.mux.HandleFunc("GET /tags", s.onGameLoop(s.getTags))
+	s.mux.HandleFunc("GET /topics", s.onGameLoop(s.getTopics))
+	s.mux.HandleFunc("GET /profiler", s.onGameLoop(s.getProfiler))
+	s.mux.HandleFunc("POST /pause", s.onGameLoop(s.pause))
+	s.mux.HandleFunc("POST /resume", s.onGameLoop(s.resume))
+	s.mux.HandleFunc("POST /step", s.onGameLoop(s.step))
+	if options.Spawn != nil {
+		s.mux.HandleFunc("POST /spawn", s.onGameLoop(s.spawn))
+	}
+	return s
+}
+
+// Get the handler that serves the debug endpoints.
+func (s *DebugServer) Handler() http.Handler {
+	return s.mux
+}
+
+// Start serving on the address in the background.
+// The address must be on localhost, as the server lets anyone who can reach it change the world.
+func (s *DebugServer) Listen(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("debug server must listen on localhost, not %q", host)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.server = &http.Server{Handler: s.mux}
+	go s.server.Serve(listener)
+	return nil
+}
+
+// Stop serving, if Listen was called.
+func (s *DebugServer) Close() error {
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Close()
+}
+
+// Answer all the queued requests using the world, which may be nil if no world is being shown.
+// Call this from the game loop between frames.
+func (s *DebugServer) Service(w *World) {
+	if s.options.Profile && w != nil && w.Profiler() == nil {
+		w.SetProfiler(NewProfiler(0))
+	}
+	for {
+		select {
+		case req := <-s.requests:
+			body, status := req.handle(w)
+			req.reply <- debugReply{body, status}
+		default:
+			return
+		}
+	}
+}
+
+// Make a handler that queues the request to be handled by Service, then writes the result as JSON.
+func (s *DebugServer) onGameLoop(handle func(w *World, r *http.Request) (any, int)) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		req := debugRequest{
+			handle: func(w *World) (any, int) { return handle(w, r) },
+			reply:  make(chan debugReply, 1),
+		}
+		timeout := time.After(debugRequestTimeout)
+		var reply debugReply
+		select {
+		case s.requests <- req:
+			select {
+			case reply = <-req.reply:
+			case <-timeout:
+				reply = debugError(http.StatusServiceUnavailable, errors.New("game loop did not answer"))
+			case <-r.Context().Done():
+				return
+			}
+		case <-timeout:
+			reply = debugError(http.StatusServiceUnavailable, errors.New("too many requests waiting for the game loop"))
+		case <-r.Context().Done():
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(reply.status)
+		json.NewEncoder(rw).Encode(reply.body)
+	}
+}
+
+func debugError(status int, err error) debugReply {
+	return debugReply{map[string]string{"error": err.Error()}, status}
+}
+
+func debugFail(status int, err error) (any, int) {
+	reply := debugError(status, err)
+	return reply.body, reply.status
+}
+
+// Returned by commands, and served by the debug server, when they need a world but none is being shown.
+var ErrNoWorld = errors.New("no world is being shown")
+
+type debugEntitySummary struct {
+	ID   EntityUUID `json:"id"`
+	Type string     `json:"type"`
+}
+
+type debugEntity struct {
+	ID       EntityUUID    `json:"id"`
+	Type     string        `json:"type"`
+	Tags     []string      `json:"tags"`
+	Parent   *EntityUUID   `json:"parent,omitempty"`
+	Children []EntityUUID  `json:"children"`
+	Position *pixel.Vec    `json:"position,omitempty"`
+	Angle    *float64      `json:"angle,omitempty"`
+	Physics  *debugPhysics `json:"physics,omitempty"`
+}
+
+type debugPhysics struct {
+	Velocity        pixel.Vec `json:"velocity"`
+	AngularVelocity float64   `json:"angularVelocity"`
+	Elasticity      float64   `json:"elasticity"`
+	Active          bool      `json:"active"`
+	Mass            *float64  `json:"mass,omitempty"`
+	AreaCenter      pixel.Vec `json:"areaCenter"`
+	AreaRadius      float64   `json:"areaRadius"`
+}
+
+// GET /entities lists every entity, or those with the tag given by ?tag=.
+func (s *DebugServer) getEntities(w *World, r *http.Request) (any, int) {
+	if w == nil {
+		return debugFail(http.StatusServiceUnavailable, ErrNoWorld)
+	}
+	q := NewQuery()
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		q = q.AllOf(tag)
+	}
+	list := make([]debugEntitySummary, 0)
+	for e := range Find[Entity](w, q) {
+		list = append(list, debugEntitySummary{e.UUID(), fmt.Sprintf("%T", e)})
+	}
+	return list, http.StatusOK
+}
+
+// GET /entities/{id} describes one entity, including its transform and physics state.
+func (s *DebugServer) getEntity(w *World, r *http.Request) (any, int) {
+	if w == nil {
+		return debugFail(http.StatusServiceUnavailable, ErrNoWorld)
+	}
+	id, err := ParseEntityUUID(r.PathValue("id"))
+	if err != nil {
+		return debugFail(http.StatusBadRequest, err)
+	}
+	e, ok := w.WithUUID(id)
+	if !ok {
+		return debugFail(http.StatusNotFound, fmt.Errorf("no entity %v in the world", id))
+	}
+	info := debugEntity{
+		ID:       id,
+		Type:     fmt.Sprintf("%T", e),
+		Tags:     w.TagsOf(e),
+		Children: make([]EntityUUID, 0),
+	}
+	if parent, ok := w.Parent(e); ok {
+		parentID := parent.UUID()
+		info.Parent = &parentID
+	}
+	for child := range w.Children(e) {
+		info.Children = append(info.Children, child.UUID())
+	}
+	if t, ok := e.(Transform); ok {
+		pos, angle := t.Position(), t.Angle()
+		info.Position, info.Angle = &pos, &angle
+	}
+	if body, ok := e.(PhysicsBody); ok {
+		center, radius := body.Shape().EffectArea()
+		info.Physics = &debugPhysics{
+			Velocity:        body.Velocity(),
+			AngularVelocity: body.AngularVelocity(),
+			Elasticity:      body.Elasticity(),
+			AreaCenter:      center,
+			AreaRadius:      radius,
+		}
+		if active, ok := body.(ActivePhysicsBody); ok {
+			mass := active.Mass()
+			info.Physics.Mass = &mass
+			info.Physics.Active = active.IsPhysicsActive()
+		}
+	}
+	return info, http.StatusOK
+}
+
+// GET /tags maps each tag to the entities that have it.
+func (s *DebugServer) getTags(w *World, r *http.Request) (any, int) {
+	if w == nil {
+		return debugFail(http.StatusServiceUnavailable, ErrNoWorld)
+	}
+	tags := make(map[string][]EntityUUID)
+	for tag, index := range w.byTags {
+		for e := range index.All() {
+			tags[tag] = append(tags[tag], e.UUID())
+		}
+	}
+	return tags, http.StatusOK
+}
+
+// GET /topics maps each world topic to the entities subscribed to it.
+func (s *DebugServer) getTopics(w *World, r *http.Request) (any, int) {
+	if w == nil {
+		return debugFail(http.StatusServiceUnavailable, ErrNoWorld)
+	}
+	topics := make(map[string][]EntityUUID)
+	for name, bus := range w.topics {
+		topics[name] = slices.Clone(bus.listeners)
+	}
+	return topics, http.StatusOK
+}
+
+// GET /profiler gets the profiler's averages, see World.SetProfiler.
+func (s *DebugServer) getProfiler(w *World, r *http.Request) (any, int) {
+	if w == nil {
+		return debugFail(http.StatusServiceUnavailable, ErrNoWorld)
+	}
+	if w.Profiler() == nil {
+		return debugFail(http.StatusNotFound, errors.New("the world has no profiler"))
+	}
+	return w.Profiler().Averages(), http.StatusOK
+}
+
+// POST /pause sets the world's time scale to 0.
+func (s *DebugServer) pause(w *World, r *http.Request) (any, int) {
+	if w == nil {
+		return debugFail(http.StatusServiceUnavailable, ErrNoWorld)
+	}
+	w.Pause()
+	return map[string]bool{"paused": w.Paused()}, http.StatusOK
+}
+
+// POST /resume restores the world's time scale from before it was paused.
+func (s *DebugServer) resume(w *World, r *http.Request) (any, int) {
+	if w == nil {
+		return debugFail(http.StatusServiceUnavailable, ErrNoWorld)
+	}
+	w.Resume()
+	return map[string]bool{"paused": w.Paused()}, http.StatusOK
+}
+
+// POST /step pauses the world and updates it for one more frame, or ?frames= more.
+func (s *DebugServer) step(w *World, r *http.Request) (any, int) {
+	if w == nil {
+		return debugFail(http.StatusServiceUnavailable, ErrNoWorld)
+	}
+	frames := 1
+	if f := r.URL.Query().Get("frames"); f != "" {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 1 {
+			return debugFail(http.StatusBadRequest, fmt.Errorf("frames must be a positive number, not %q", f))
+		}
+		frames = n
+	}
+	w.Pause()
+	w.StepFrames(frames)
+	return map[string]int{"steps": w.PendingSteps()}, http.StatusOK
+}
+
+// POST /spawn?name=&x=&y= spawns the named entity at the position using the Spawn option, or ?count= of them.
+func (s *DebugServer) spawn(w *World, r *http.Request) (any, int) {
+	if w == nil {
+		return debugFail(http.StatusServiceUnavailable, ErrNoWorld)
+	}
+	query := r.URL.Query()
+	x, errX := strconv.ParseFloat(query.Get("x"), 64)
+	y, errY := strconv.ParseFloat(query.Get("y"), 64)
+	if errX != nil || errY != nil {
+		return debugFail(http.StatusBadRequest, errors.New("x and y must be numbers"))
+	}
+	count := 1
+	if c := query.Get("count"); c != "" {
+		n, err := strconv.Atoi(c)
+		if err != nil || n < 1 {
+			return debugFail(http.StatusBadRequest, fmt.Errorf("count must be a positive number, not %q", c))
+		}
+		count = n
+	}
+	out, err := s.options.Spawn(w, query.Get("name"), pixel.V(x, y), count)
+	if err != nil {
+		return debugFail(http.StatusBadRequest, err)
+	}
+	return map[string]string{"result": out}, http.StatusOK
+}
@@ -0,0 +1,202 @@
+package ent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopxl/pixel"
+)
+
+type debugTestEntity struct {
+	CoreEntity
+	WithTransform
+}
+
+// Make a request to the server, servicing it with the world from this goroutine until it is answered.
+func debugRequestTo(t *testing.T, srv *httptest.Server, s *DebugServer, w *World, method, path string, out any) int {
+	t.Helper()
+	type result struct {
+		resp *http.Response
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		req, _ := http.NewRequest(method, srv.URL+path, nil)
+		resp, err := srv.Client().Do(req)
+		done <- result{resp, err}
+	}()
+	for {
+		s.Service(w)
+		select {
+		case r := <-done:
+			if r.err != nil {
+				t.Fatal(r.err)
+			}
+			defer r.resp.Body.Close()
+			body, _ := io.ReadAll(r.resp.Body)
+			if out != nil {
+				if err := json.Unmarshal(body, out); err != nil {
+					t.Fatalf("%s %s: bad json %q: %v", method, path, body, err)
+				}
+			}
+			return r.resp.StatusCode
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestDebugServerListenOnlyOnLocalhost(t *testing.T) {
+	s := NewDebugServer(DebugServerOptions{})
+	for _, addr := range []string{"0.0.0.0:0", ":0", "192.168.1.1:0", "example.com:0", "not an address"} {
+		if err := s.Listen(addr); err == nil {
+			s.Close()
+			t.Errorf("listening on %q should fail", addr)
+		}
+	}
+	if err := s.Listen("127.0.0.1:0"); err != nil {
+		t.Fatalf("listening on localhost should work: %v", err)
+	}
+	s.Close()
+}
+
+func TestDebugServerWaitsForService(t *testing.T) {
+	s := NewDebugServer(DebugServerOptions{})
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	done := make(chan int, 1)
+	go func() {
+		resp, err := srv.Client().Get(srv.URL + "/entities")
+		if err != nil {
+			done <- 0
+			return
+		}
+		resp.Body.Close()
+		done <- resp.StatusCode
+	}()
+	select {
+	case <-done:
+		t.Fatal("request was answered before Service was called")
+	case <-time.After(50 * time.Millisecond):
+	}
+	w := NewWorld()
+	for {
+		s.Service(w)
+		select {
+		case status := <-done:
+			if status != http.StatusOK {
+				t.Fatalf("expected 200, got %d", status)
+			}
+			return
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestDebugServerEndpoints(t *testing.T) {
+	var spawned []string
+	s := NewDebugServer(DebugServerOptions{
+		Spawn: func(w *World, name string, at pixel.Vec, count int) (string, error) {
+			if name != "thing" {
+				return "", fmt.Errorf("no %q", name)
+			}
+			for range count {
+				e := &debugTestEntity{}
+				e.SetPosition(at)
+				w.AddNow(e)
+			}
+			spawned = append(spawned, fmt.Sprintf("%d at %v", count, at))
+			return "ok", nil
+		},
+		Profile: true,
+	})
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	if status := debugRequestTo(t, srv, s, nil, "GET", "/entities", nil); status != http.StatusServiceUnavailable {
+		t.Errorf("without a world: expected 503, got %d", status)
+	}
+
+	w := NewWorld()
+	parent, child := &debugTestEntity{}, &debugTestEntity{}
+	parent.SetPosition(pixel.V(1, 2))
+	w.AddNow(parent, child)
+	w.SetParent(child, parent)
+	w.AddTags(parent, "boss")
+
+	var list []debugEntitySummary
+	if status := debugRequestTo(t, srv, s, w, "GET", "/entities?tag=boss", &list); status != http.StatusOK || len(list) != 1 || list[0].ID != parent.UUID() {
+		t.Errorf("GET /entities?tag=boss: got %d %+v", status, list)
+	}
+	var info debugEntity
+	if status := debugRequestTo(t, srv, s, w, "GET", "/entities/"+parent.UUID().String(), &info); status != http.StatusOK {
+		t.Errorf("GET /entities/{id}: got %d", status)
+	}
+	if info.Position == nil || *info.Position != pixel.V(1, 2) || len(info.Children) != 1 || info.Children[0] != child.UUID() || info.Tags[0] != "boss" {
+		t.Errorf("GET /entities/{id}: got %+v", info)
+	}
+	if status := debugRequestTo(t, srv, s, w, "GET", "/entities/nonsense", nil); status != http.StatusBadRequest {
+		t.Errorf("GET /entities/nonsense: expected 400, got %d", status)
+	}
+	w.RemoveNow(child)
+	if status := debugRequestTo(t, srv, s, w, "GET", "/entities/"+child.UUID().String(), nil); status != http.StatusNotFound {
+		t.Errorf("GET removed entity: expected 404, got %d", status)
+	}
+	var tags map[string][]EntityUUID
+	if status := debugRequestTo(t, srv, s, w, "GET", "/tags", &tags); status != http.StatusOK || len(tags["boss"]) != 1 {
+		t.Errorf("GET /tags: got %d %v", status, tags)
+	}
+
+	if status := debugRequestTo(t, srv, s, w, "POST", "/pause", nil); status != http.StatusOK || !w.Paused() {
+		t.Errorf("POST /pause: got %d, paused %v", status, w.Paused())
+	}
+	if status := debugRequestTo(t, srv, s, w, "POST", "/step?frames=3", nil); status != http.StatusOK || w.pendingSteps != 3 {
+		t.Errorf("POST /step: got %d, %d steps", status, w.pendingSteps)
+	}
+	if status := debugRequestTo(t, srv, s, w, "POST", "/step?frames=0", nil); status != http.StatusBadRequest {
+		t.Errorf("POST /step?frames=0: expected 400, got %d", status)
+	}
+	if status := debugRequestTo(t, srv, s, w, "POST", "/resume", nil); status != http.StatusOK || w.Paused() {
+		t.Errorf("POST /resume: got %d, paused %v", status, w.Paused())
+	}
+	if status := debugRequestTo(t, srv, s, w, "GET", "/pause", nil); status != http.StatusMethodNotAllowed {
+		t.Errorf("GET /pause: expected 405, got %d", status)
+	}
+
+	if status := debugRequestTo(t, srv, s, w, "POST", "/spawn?name=thing&x=3&y=4&count=2", nil); status != http.StatusOK {
+		t.Errorf("POST /spawn: got %d", status)
+	}
+	if strings.Join(spawned, ",") != "2 at Vec(3, 4)" {
+		t.Errorf("POST /spawn: spawned %v", spawned)
+	}
+	for _, bad := range []string{"name=other&x=0&y=0", "name=thing&x=a&y=0", "name=thing&x=0&y=0&count=0"} {
+		if status := debugRequestTo(t, srv, s, w, "POST", "/spawn?"+bad, nil); status != http.StatusBadRequest {
+			t.Errorf("POST /spawn?%s: expected 400, got %d", bad, status)
+		}
+	}
+
+	if w.Profiler() == nil {
+		t.Fatal("the Profile option should give the world a profiler")
+	}
+	if status := debugRequestTo(t, srv, s, w, "GET", "/profiler", nil); status != http.StatusOK {
+		t.Errorf("GET /profiler: got %d", status)
+	}
+}
+
+func TestDebugServerWithoutSpawn(t *testing.T) {
+	s := NewDebugServer(DebugServerOptions{})
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	w := NewWorld()
+	if status := debugRequestTo(t, srv, s, w, "POST", "/spawn?name=thing&x=0&y=0", nil); status != http.StatusNotFound {
+		t.Errorf("POST /spawn without a Spawn option: expected 404, got %d", status)
+	}
+	if w.Profiler() != nil {
+		t.Error("worlds should be left without a profiler unless Profile is set")
+	}
+}
@@ -0,0 +1,107 @@
+package ent
+
+import (
+	"cmp"
+	"slices"
+)
+
+// The default number of cascading waves of deferred messages that are delivered in a single frame.
+const DefaultMaxMessageDepth = 8
+
+// A message that should be delivered before or after other deferred messages.
+type PrioritisedMessage interface {
+	// Called to get the delivery priority of this message.
+	// Higher values will be delivered first.
+	Priority() int
+}
+
+type queuedMessage struct {
+	data       any
+	recipients []EntityUUID
+	trace      MessageTrace
+}
+
+// Queue a message to be sent to all entities subscribed to the bus (or matching wildcard topics) right now.
+// The message will be delivered during the message phase of World.Update.
+func EmitLater(w *World, b *Bus, d any) {
+	if w.buffering() {
+		w.commands.record(func(w *World) { EmitLater(w, b, d) })
+		return
+	}
+	w.queueMessage(w.newTrace(TraceEmit, busName(b)), d, w.recipientsOf(b))
+}
+
+// Queue a message to be sent to the specified entities directly, bypassing any bus.
+// The message will be delivered during the message phase of World.Update.
+func EmitDirectlyLater(w *World, d any, es ...EntityUUIDer) {
+	if w.buffering() {
+		w.commands.record(func(w *World) { EmitDirectlyLater(w, d, es...) })
+		return
+	}
+	recipients := make([]EntityUUID, len(es))
+	for i, e := range es {
+		recipients[i] = e.UUID()
+	}
+	w.queueMessage(w.newTrace(TraceEmitDirectly, ""), d, recipients)
+}
+
+// Queue a message to be sent directly to every entity that has the given tag right now.
+// The message will be delivered during the message phase of World.Update.
+func EmitToTagLater(w *World, tag string, d any) {
+	if w.buffering() {
+		w.commands.record(func(w *World) { EmitToTagLater(w, tag, d) })
+		return
+	}
+	recipients := make([]EntityUUID, 0)
+	for e := range w.WithTag(tag) {
+		recipients = append(recipients, e.UUID())
+	}
+	w.queueMessage(w.newTrace(TraceEmitToTag, tag), d, recipients)
+}
+
+// Set the maximum number of cascading waves of deferred messages delivered each frame.
+// Messages queued by the final wave are held until the next frame.
+func (w *World) SetMaxMessageDepth(depth int) {
+	w.maxMessageDepth = max(depth, 1)
+}
+
+func (w *World) queueMessage(trace MessageTrace, d any, recipients []EntityUUID) {
+	if w.tracer != nil {
+		queuedTrace := trace
+		queuedTrace.Queued = true
+		queuedTrace.PayloadType = payloadType(d)
+		queuedTrace.Recipients = recipients
+		w.tracer.Trace(queuedTrace)
+	}
+	w.queuedMessages = append(w.queuedMessages, queuedMessage{d, recipients, trace})
+}
+
+// Deliver queued messages in waves, highest priority first then in the order they were queued.
+// Messages queued while a wave is being delivered make up the next wave.
+// Recipients that are neither in the world nor queued to be added at delivery time are dropped.
+func (w *World) deliverQueuedMessages() {
+	for wave := 0; wave < w.maxMessageDepth && len(w.queuedMessages) > 0; wave++ {
+		msgs := w.queuedMessages
+		w.queuedMessages = nil
+		slices.SortStableFunc(msgs, func(a, b queuedMessage) int {
+			return cmp.Compare(messagePriority(b.data), messagePriority(a.data))
+		})
+		for _, msg := range msgs {
+			trace := msg.trace
+			trace.Frame = w.frame
+			trace.Deferred = true
+			if q, ok := msg.data.(queuedQuery); ok {
+				q.answer(w, trace)
+				continue
+			}
+			emitHelper(w, trace, msg.data, msg.recipients...)
+		}
+	}
+}
+
+func messagePriority(d any) int {
+	if p, ok := d.(PrioritisedMessage); ok {
+		return p.Priority()
+	}
+	return 0
+}
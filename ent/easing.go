@@ -0,0 +1,125 @@
+package ent
+
+import (
+	"math"
+
+	"github.com/gopxl/pixel"
+)
+
+// A curve that maps linear progress (0 to 1) to eased progress.
+// Eased progress starts at 0 and ends at 1, but may go outside of that range in between.
+type Easing func(float64) float64
+
+// Keep progress as it is.
+func EaseLinear(t float64) float64 { return t }
+
+// Start slowly, accelerating with the square of progress.
+func EaseInQuad(t float64) float64 { return t * t }
+
+// Start quickly, decelerating with the square of progress.
+func EaseOutQuad(t float64) float64 { return 1 - (1-t)*(1-t) }
+
+// Speed up then slow down, following a quadratic curve.
+func EaseInOutQuad(t float64) float64 { return inOut(EaseInQuad, t) }
+
+// Start slowly, accelerating with the cube of progress.
+func EaseInCubic(t float64) float64 { return t * t * t }
+
+// Start quickly, decelerating with the cube of progress.
+func EaseOutCubic(t float64) float64 { return 1 - math.Pow(1-t, 3) }
+
+// Speed up then slow down, following a cubic curve.
+func EaseInOutCubic(t float64) float64 { return inOut(EaseInCubic, t) }
+
+// Start slowly, following a quarter of a sine wave.
+func EaseInSine(t float64) float64 { return 1 - math.Cos(t*math.Pi/2) }
+
+// Start quickly, following a quarter of a sine wave.
+func EaseOutSine(t float64) float64 { return math.Sin(t * math.Pi / 2) }
+
+// Speed up then slow down, following half of a sine wave.
+func EaseInOutSine(t float64) float64 { return -(math.Cos(math.Pi*t) - 1) / 2 }
+
+// Start very slowly, accelerating exponentially.
+func EaseInExpo(t float64) float64 {
+	if t <= 0 {
+		return 0
+	}
+	return math.Pow(2, 10*t-10)
+}
+
+// Start very quickly, decelerating exponentially.
+func EaseOutExpo(t float64) float64 { return 1 - EaseInExpo(1-t) }
+
+// Speed up then slow down, following an exponential curve.
+func EaseInOutExpo(t float64) float64 { return inOut(EaseInExpo, t) }
+
+// Pull back slightly below 0 before accelerating to the end.
+func EaseInBack(t float64) float64 {
+	const c1 = 1.70158
+	return (c1+1)*t*t*t - c1*t*t
+}
+
+// Overshoot slightly past 1 before settling at the end.
+func EaseOutBack(t float64) float64 { return 1 - EaseInBack(1-t) }
+
+// Pull back at the start and overshoot at the end.
+func EaseInOutBack(t float64) float64 { return inOut(EaseInBack, t) }
+
+// Overshoot then wobble around 1 like a spring before settling.
+func EaseOutElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*(2*math.Pi/3)) + 1
+}
+
+// Wobble around 0 like a spring before shooting to the end.
+func EaseInElastic(t float64) float64 { return 1 - EaseOutElastic(1-t) }
+
+// Wobble at both the start and the end like a spring.
+func EaseInOutElastic(t float64) float64 { return inOut(EaseInElastic, t) }
+
+// Bounce against 1 a few times before settling, like a dropped ball.
+func EaseOutBounce(t float64) float64 {
+	const n1, d1 = 7.5625, 2.75
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	default:
+		t -= 2.625 / d1
+		return n1*t*t + 0.984375
+	}
+}
+
+// Bounce against 0 a few times before shooting to the end.
+func EaseInBounce(t float64) float64 { return 1 - EaseOutBounce(1-t) }
+
+// Bounce at both the start and the end.
+func EaseInOutBounce(t float64) float64 { return inOut(EaseInBounce, t) }
+
+// Build an in-out curve from the first half of an in curve and its mirror image.
+func inOut(in Easing, t float64) float64 {
+	if t < 0.5 {
+		return in(t*2) / 2
+	}
+	return 1 - in((1-t)*2)/2
+}
+
+// Move from current towards target, independently of framerate.
+// Remaining is the fraction of the distance that will be left to go after one second.
+func Damp(current, target, remaining, dt float64) float64 {
+	return target + (current-target)*math.Pow(remaining, dt)
+}
+
+// Move from current towards target, independently of framerate.
+// Remaining is the fraction of the distance that will be left to go after one second.
+func DampVec(current, target pixel.Vec, remaining, dt float64) pixel.Vec {
+	return pixel.Lerp(target, current, math.Pow(remaining, dt))
+}
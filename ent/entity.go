@@ -5,7 +5,10 @@ import (
 	"github.com/gopxl/pixel/pixelgl"
 )
 
-type EntityUUID string
+// A handle to an entity, allocated by the world it is added to.
+// It is made of a slot index and a generation, so handles to removed entities are detected as stale even when their slot is reused.
+// The zero value never refers to an entity.
+type EntityUUID uint64
 
 func (e EntityUUID) UUID() EntityUUID { return e }
 
@@ -19,7 +22,7 @@ type Drawer interface {
 	Draw(win *pixelgl.Window, world *World, worldToScreen pixel.Matrix)
 	// Called to get the draw layer for this entity.
 	// Higher values will be drawn first (appear below other objects).
-	// Should NEVER change after entity has been created.
+	// If this changes after the entity has been added, call World.RefreshLayers.
 	DrawLayer() int
 }
 
@@ -30,17 +33,20 @@ type Updater interface {
 	Update(win *pixelgl.Window, world *World, dt float64)
 	// Called to get the update layer for this entity.
 	// Higher values will be updated first.
-	// Should NEVER change after entity has been created.
+	// If this changes after the entity has been added, call World.RefreshLayers.
 	UpdateLayer() int
 }
 
+// Something that refers to an entity by its handle, such as the entity itself, a Ref, or the handle.
 type EntityUUIDer interface {
 	UUID() EntityUUID
 }
 
 // An object that can be added to a world.
+// Entities must compose CoreEntity, which holds the UUID the world gives each entity when it is added.
 type Entity interface {
 	EntityUUIDer
 	AfterAdd(*World)
 	HandleMessage(*World, any)
+	core() *CoreEntity
 }
@@ -2,10 +2,7 @@ module ent
 
 go 1.23.1
 
-require (
-	github.com/google/uuid v1.6.0
-	github.com/gopxl/pixel v1.0.0
-)
+require github.com/gopxl/pixel v1.0.0
 
 require (
 	github.com/faiface/glhf v0.0.0-20211013000516-57b20770c369 // indirect
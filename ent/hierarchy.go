@@ -0,0 +1,147 @@
+package ent
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/gopxl/pixel"
+	"github.com/gopxl/pixel/pixelgl"
+)
+
+// A transform that can be made relative to the transform of a parent entity.
+type ParentedTransform interface {
+	// Called by the world when the entity is given a new parent, or with nil when it is unparented.
+	SetParentTransform(Transform)
+}
+
+// Make the child entity a child of the parent entity.
+// Both entities may be in the world or queued to be added, but must be one or the other.
+// Removing the parent will also remove the child.
+// Children are updated straight after their parent, and are drawn straight after it unless their draw layer is higher than the parent's.
+// If the child is a ParentedTransform and the parent is a Transform, the child's transform will become relative to the parent.
+// Panics if the parent is the child itself or one of its descendants, as the hierarchy can not have cycles.
+func (w *World) SetParent(child, parent Entity) {
+	if w.buffering() {
+		w.commands.record(func(w *World) { w.SetParent(child, parent) })
+		return
+	}
+	childID, parentID := handleOf(child, "set the parent of"), handleOf(parent, "make a child of")
+	for id, ok := parentID, true; ok; id, ok = w.parents[id] {
+		if id == childID {
+			panic(fmt.Sprintf("cannot make %v a child of %v, as that would make a cycle in the hierarchy", childID, parentID))
+		}
+	}
+	w.Unparent(child)
+	w.parents[childID] = parentID
+	w.children[parentID] = append(w.children[parentID], childID)
+	if pt, ok := child.(ParentedTransform); ok {
+		if t, ok := parent.(Transform); ok {
+			pt.SetParentTransform(t)
+		}
+	}
+}
+
+// Remove the entity from its parent, if it has one.
+func (w *World) Unparent(child Entity) {
+	if w.buffering() {
+		w.commands.record(func(w *World) { w.Unparent(child) })
+		return
+	}
+	childID := child.UUID()
+	parentID, ok := w.parents[childID]
+	if !ok {
+		return
+	}
+	delete(w.parents, childID)
+	w.children[parentID] = slices.DeleteFunc(w.children[parentID], func(id EntityUUID) bool {
+		return id == childID
+	})
+	if len(w.children[parentID]) == 0 {
+		delete(w.children, parentID)
+	}
+	if pt, ok := child.(ParentedTransform); ok {
+		pt.SetParentTransform(nil)
+	}
+}
+
+// Get the parent of the entity, if it has one that is in the world.
+func (w *World) Parent(e EntityUUIDer) (Entity, bool) {
+	parentID, ok := w.parents[e.UUID()]
+	if !ok {
+		return nil, false
+	}
+	return w.WithUUID(parentID)
+}
+
+// Get the children of the entity that are in the world.
+func (w *World) Children(e EntityUUIDer) iter.Seq[Entity] {
+	childIDs := slices.Clone(w.children[e.UUID()])
+	return func(yield func(Entity) bool) {
+		for _, id := range childIDs {
+			child, ok := w.WithUUID(id)
+			if !ok {
+				continue
+			}
+			if !yield(child) {
+				return
+			}
+		}
+	}
+}
+
+// Is the entity visited by its parent, rather than directly, when iterating entities that are Ts?
+func isVisitedByParent[T any](w *World, e EntityUUIDer) bool {
+	parent, ok := w.Parent(e)
+	if !ok {
+		return false
+	}
+	_, ok = parent.(T)
+	return ok
+}
+
+// Get the children of the entity that are Ts, ordered by the given order (highest first).
+// Most entities have no children, so those return straight away without allocating.
+func childrenOfType[T any](w *World, e EntityUUIDer, orderOf func(T) int) []T {
+	if len(w.children[e.UUID()]) == 0 {
+		return nil
+	}
+	children := slices.Collect(OfType[T](w.Children(e)))
+	slices.SortStableFunc(children, func(a, b T) int {
+		return cmp.Compare(orderOf(b), orderOf(a))
+	})
+	return children
+}
+
+func (w *World) updateTree(win *pixelgl.Window, e Updater, dt float64) {
+	w.activeEntity = e.UUID()
+	w.profileEntity(SectionUpdate, e, e.UpdateLayer(), func() { e.Update(win, w, w.dtFor(e, dt)) })
+	for _, child := range childrenOfType(w, e, Updater.UpdateLayer) {
+		w.updateTree(win, child, dt)
+	}
+}
+
+func (w *World) drawTree(win *pixelgl.Window, e Drawer, worldToScreen pixel.Matrix) {
+	children := childrenOfType(w, e, Drawer.DrawLayer)
+	for _, child := range children {
+		if child.DrawLayer() > e.DrawLayer() {
+			w.drawTree(win, child, worldToScreen)
+		}
+	}
+	w.profileEntity(SectionDraw, e, e.DrawLayer(), func() { e.Draw(win, w, worldToScreen) })
+	for _, child := range children {
+		if child.DrawLayer() <= e.DrawLayer() {
+			w.drawTree(win, child, worldToScreen)
+		}
+	}
+}
+
+// Remove the entity's children from the world, and forget about its place in the hierarchy.
+func (w *World) removeFromHierarchy(e Entity) {
+	for child := range w.Children(e) {
+		w.RemoveNow(child)
+	}
+	delete(w.children, e.UUID())
+	w.Unparent(e)
+}
@@ -0,0 +1,41 @@
+package ent
+
+import "testing"
+
+type testEntity struct {
+	CoreEntity
+}
+
+func TestSetParentRejectsCycles(t *testing.T) {
+	w := NewWorld()
+	a, b, c := &testEntity{}, &testEntity{}, &testEntity{}
+	w.AddNow(a, b, c)
+	w.SetParent(b, a)
+	w.SetParent(c, b)
+	for _, tc := range []struct {
+		name          string
+		child, parent Entity
+	}{
+		{"self", a, a},
+		{"parent", a, b},
+		{"grandparent", a, c},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected a panic")
+				}
+			}()
+			w.SetParent(tc.child, tc.parent)
+		})
+	}
+	if p, ok := w.Parent(a); ok {
+		t.Fatalf("a should have no parent, has %v", p.UUID())
+	}
+	// Moving a child to a sibling is not a cycle
+	w.SetParent(c, a)
+	w.RemoveNow(a)
+	if w.Has(b) || w.Has(c) {
+		t.Fatal("children should be removed with their parent")
+	}
+}
@@ -0,0 +1,131 @@
+package ent
+
+import (
+	"fmt"
+)
+
+func newEntityUUID(index, generation uint32) EntityUUID {
+	return EntityUUID(uint64(generation)<<32 | uint64(index))
+}
+
+func (e EntityUUID) index() uint32 {
+	return uint32(e)
+}
+
+func (e EntityUUID) generation() uint32 {
+	return uint32(e >> 32)
+}
+
+// Get the string form of the handle, such as "12v3" (slot 12, generation 3).
+func (e EntityUUID) String() string {
+	return fmt.Sprintf("%dv%d", e.index(), e.generation())
+}
+
+// Parse the string form of a handle, as returned by EntityUUID.String.
+func ParseEntityUUID(s string) (EntityUUID, error) {
+	var index, generation uint32
+	if _, err := fmt.Sscanf(s, "%dv%d", &index, &generation); err != nil {
+		return 0, fmt.Errorf("invalid entity uuid %q: %w", s, err)
+	}
+	return newEntityUUID(index, generation), nil
+}
+
+func (e EntityUUID) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+func (e *EntityUUID) UnmarshalText(text []byte) error {
+	id, err := ParseEntityUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*e = id
+	return nil
+}
+
+type slotState uint8
+
+const (
+	slotFree slotState = iota
+	slotQueued
+	slotLive
+)
+
+// A place in the world's entity table.
+type entitySlot struct {
+	generation uint32
+	state      slotState
+	entity     Entity
+}
+
+// Get the entity's handle to use it for something, panicking if it does not have one yet.
+// Entities only get a handle once they have been added or queued to be added, so the zero handle would silently refer to nothing.
+// During a ParallelSafe update, Add is buffered, so entities added by it do not have a handle until the end of the layer.
+func handleOf(e EntityUUIDer, use string) EntityUUID {
+	id := e.UUID()
+	if id == 0 {
+		panic(fmt.Sprintf("cannot %s %T before it has been added to a world", use, e))
+	}
+	return id
+}
+
+// Get the slot that the handle refers to, if the handle is not stale.
+func (w *worldState) slotOf(id EntityUUID) (*entitySlot, bool) {
+	if id == 0 || int(id.index()) >= len(w.slots) {
+		return nil, false
+	}
+	slot := &w.slots[id.index()]
+	if slot.state == slotFree || slot.generation != id.generation() {
+		return nil, false
+	}
+	return slot, true
+}
+
+// Make sure the entity has a handle that belongs to this world, allocating a slot in the given state if it does not.
+// Entities that were removed and are being added again get a new handle.
+// Panics if the entity is still in, or queued to be added to, another world.
+func (w *World) claimSlot(e Entity, state slotState) *entitySlot {
+	core := e.core()
+	if core.world == w.worldState {
+		if slot, ok := w.slotOf(core.uuid); ok && slot.entity == e {
+			if state > slot.state {
+				slot.state = state
+			}
+			return slot
+		}
+	} else if core.world != nil {
+		if _, ok := core.world.slotOf(core.uuid); ok {
+			panic(fmt.Sprintf("cannot add %T %v to a world while it is in another, as an entity can only be in one world at a time", e, core.uuid))
+		}
+	}
+	var index uint32
+	if n := len(w.freeSlots); n > 0 {
+		index = w.freeSlots[n-1]
+		w.freeSlots = w.freeSlots[:n-1]
+	} else {
+		index = uint32(len(w.slots))
+		w.slots = append(w.slots, entitySlot{})
+	}
+	slot := &w.slots[index]
+	if slot.generation == 0 {
+		slot.generation = 1
+	}
+	slot.state = state
+	slot.entity = e
+	core.uuid = newEntityUUID(index, slot.generation)
+	core.world = w.worldState
+	return slot
+}
+
+// Free the entity's slot, making any handles to it stale.
+func (w *World) freeSlot(id EntityUUID) {
+	slot, ok := w.slotOf(id)
+	if !ok {
+		return
+	}
+	slot.generation++
+	slot.state = slotFree
+	slot.entity = nil
+	w.freeSlots = append(w.freeSlots, id.index())
+	w.slotsFreed++
+}
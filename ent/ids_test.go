@@ -0,0 +1,116 @@
+package ent
+
+import (
+	"strings"
+	"testing"
+)
+
+// How many entities are spawned and despawned each frame in the spawn heavy benchmarks.
+const spawnsPerFrame = 1000
+
+// Spawn a frame's worth of entities, update, then remove them all and update again.
+func BenchmarkSpawnHeavyFrame(b *testing.B) {
+	w := NewWorld()
+	spawned := make([]Entity, spawnsPerFrame)
+	for range b.N {
+		for i := range spawned {
+			spawned[i] = &testEntity{}
+		}
+		w.Add(spawned...)
+		w.Update(nil, 1.0/60.0)
+		for _, e := range spawned {
+			if _, ok := w.WithUUID(e.UUID()); !ok {
+				b.Fatal("spawned entity should be in the world")
+			}
+		}
+		w.Remove(spawned...)
+		w.Update(nil, 1.0/60.0)
+	}
+}
+
+// Allocate, look up, then free a frame's worth of handles, without the rest of the frame.
+func BenchmarkSpawnHandles(b *testing.B) {
+	w := NewWorld()
+	spawned := make([]Entity, spawnsPerFrame)
+	for i := range spawned {
+		spawned[i] = &testEntity{}
+	}
+	for range b.N {
+		for _, e := range spawned {
+			w.claimSlot(e, slotLive)
+		}
+		for _, e := range spawned {
+			if _, ok := w.slotOf(e.UUID()); !ok {
+				b.Fatal("spawned entity should have a slot")
+			}
+		}
+		for _, e := range spawned {
+			w.freeSlot(e.UUID())
+		}
+	}
+}
+
+func TestRemovedHandlesAreStale(t *testing.T) {
+	w := NewWorld()
+	a := &testEntity{}
+	w.AddNow(a)
+	old := a.UUID()
+	w.RemoveNow(a)
+	b := &testEntity{}
+	w.AddNow(b)
+	if b.UUID().index() != old.index() {
+		t.Fatalf("expected slot %d to be reused, got %d", old.index(), b.UUID().index())
+	}
+	if _, ok := w.WithUUID(old); ok {
+		t.Fatal("handle to a removed entity should be stale")
+	}
+	if e, ok := w.WithUUID(b.UUID()); !ok || e != b {
+		t.Fatal("new handle should find the new entity")
+	}
+}
+
+func TestUsingEntityBeforeAddPanics(t *testing.T) {
+	w := NewWorld()
+	added := &testEntity{}
+	w.AddNow(added)
+	for _, tt := range []struct {
+		name string
+		use  func(e Entity)
+	}{
+		{"SetParent", func(e Entity) { w.SetParent(e, added) }},
+		{"SetParent to", func(e Entity) { w.SetParent(added, e) }},
+		{"Subscribe", func(e Entity) { Subscribe(NewBus(), e) }},
+		{"RefTo", func(e Entity) { RefTo(e) }},
+		{"AfterFor", func(e Entity) { w.AfterFor(e, 1, func() {}) }},
+		{"TweenFor", func(e Entity) { w.TweenFor(e, Delay(1)) }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil || !strings.Contains(r.(string), "before it has been added") {
+					t.Fatalf("expected a panic about using the entity before adding it, got %v", r)
+				}
+			}()
+			tt.use(&testEntity{})
+		})
+	}
+}
+
+func TestEntityIsInOneWorldAtATime(t *testing.T) {
+	first, second := NewWorld(), NewWorld()
+	e := &testEntity{}
+	first.Add(e)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("adding an entity queued in another world should panic")
+			}
+		}()
+		second.AddNow(e)
+	}()
+	first.Update(nil, 1)
+	first.RemoveNow(e)
+	second.AddNow(e)
+	if !second.Has(e) || first.Has(e) {
+		t.Fatal("a removed entity should be able to join another world")
+	}
+}
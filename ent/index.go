@@ -6,14 +6,17 @@ import (
 	"sort"
 )
 
+// Create an index that keeps its items ordered by the order function (highest first).
+// Items with equal order are kept in the order they were added.
 func NewOrderedIndex[T EntityUUIDer](orderFunc func(T) int) *Index[T] {
 	return &Index[T]{
 		orderedItems:  make([]orderedItem[T], 0),
-		containsUUIDs: make(map[EntityUUID]struct{}),
+		containsUUIDs: make(map[EntityUUID]int),
 		orderOf:       orderFunc,
 	}
 }
 
+// Create an index that keeps its items in the order they were added.
 func NewUnorderedIndex[T EntityUUIDer]() *Index[T] {
 	return NewOrderedIndex(func(t T) int { return 0 })
 }
@@ -24,8 +27,9 @@ type orderedItem[T any] struct {
 }
 
 type Index[T EntityUUIDer] struct {
-	orderedItems  []orderedItem[T]
-	containsUUIDs map[EntityUUID]struct{}
+	orderedItems []orderedItem[T]
+	// The order each item had when it was added, so it can be found again even if its order has changed.
+	containsUUIDs map[EntityUUID]int
 	orderOf       func(T) int
 }
 
@@ -45,6 +49,14 @@ func (oi *Index[T]) RemoveUntyped(item any) bool {
 	return oi.Remove(itemTyped)
 }
 
+func (oi *Index[T]) RefreshUntyped(item any) bool {
+	itemTyped, ok := item.(T)
+	if !ok {
+		return false
+	}
+	return oi.Refresh(itemTyped)
+}
+
 func (oi *Index[T]) HasUntyped(item any) bool {
 	itemTyped, ok := item.(T)
 	if !ok {
@@ -58,8 +70,8 @@ func (oi *Index[T]) Add(item T) bool {
 	if _, ok := oi.containsUUIDs[id]; ok {
 		return false
 	}
-	oi.containsUUIDs[id] = struct{}{}
 	order := oi.orderOf(item)
+	oi.containsUUIDs[id] = order
 	insertIndex := sort.Search(
 		len(oi.orderedItems),
 		func(i int) bool {
@@ -76,11 +88,11 @@ func (oi *Index[T]) Add(item T) bool {
 
 func (oi *Index[T]) Remove(item T) bool {
 	id := item.UUID()
-	if _, ok := oi.containsUUIDs[id]; !ok {
+	order, ok := oi.containsUUIDs[id]
+	if !ok {
 		return false
 	}
 	delete(oi.containsUUIDs, id)
-	order := oi.orderOf(item)
 	// We should only start the search from the position where the orders start to equal (can skip all orders below that)
 	startSearchIndex := sort.Search(
 		len(oi.orderedItems),
@@ -88,17 +100,38 @@ func (oi *Index[T]) Remove(item T) bool {
 			return order >= oi.orderedItems[i].order
 		},
 	)
-	for i := startSearchIndex; i < len(oi.orderedItems); i++ {
-		checkItem := oi.orderedItems[i]
-		if checkItem.item.UUID() == id {
-			oi.orderedItems = slices.Delete(
-				oi.orderedItems,
-				i, i+1,
-			)
-			return true
+	if i, ok := oi.find(id, startSearchIndex); ok {
+		oi.orderedItems = slices.Delete(oi.orderedItems, i, i+1)
+		return true
+	}
+	// The items have got out of order somehow, so fall back to checking all of them
+	if i, ok := oi.find(id, 0); ok {
+		oi.orderedItems = slices.Delete(oi.orderedItems, i, i+1)
+		return true
+	}
+	return false
+}
+
+// Find the position of the item with the given id, starting the search at from.
+func (oi *Index[T]) find(id EntityUUID, from int) (int, bool) {
+	for i := from; i < len(oi.orderedItems); i++ {
+		if oi.orderedItems[i].item.UUID() == id {
+			return i, true
 		}
 	}
-	panic("somthing has gone wrong with maintaining the index")
+	return 0, false
+}
+
+// Move the item to the right place if its order has changed since it was added.
+// Returns true if the item was moved.
+func (oi *Index[T]) Refresh(item T) bool {
+	order, ok := oi.containsUUIDs[item.UUID()]
+	if !ok || order == oi.orderOf(item) {
+		return false
+	}
+	oi.Remove(item)
+	oi.Add(item)
+	return true
 }
 
 func (index *Index[T]) All() iter.Seq[T] {
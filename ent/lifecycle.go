@@ -0,0 +1,77 @@
+package ent
+
+import (
+	"slices"
+)
+
+// An object that wants to know when it is about to be removed from a world.
+type BeforeRemover interface {
+	// Called by RemoveNow while the entity (and its children) are still in the world.
+	BeforeRemove(*World)
+}
+
+type observer[F any] struct {
+	id int
+	fn F
+}
+
+type observerList[F any] struct {
+	observers []observer[F]
+	nextID    int
+}
+
+// Add the observer to the list, returning a function to remove it again.
+func (l *observerList[F]) add(fn F) func() {
+	id := l.nextID
+	l.nextID++
+	l.observers = append(l.observers, observer[F]{id, fn})
+	return func() {
+		l.observers = slices.DeleteFunc(l.observers, func(o observer[F]) bool {
+			return o.id == id
+		})
+	}
+}
+
+// Call each observer that was registered when notify was called, in the order they were registered.
+func (l *observerList[F]) notify(call func(F)) {
+	for _, o := range slices.Clone(l.observers) {
+		call(o.fn)
+	}
+}
+
+// Call the function every time an entity is added to the world, after its AfterAdd has been called.
+// Returns a function that stops the observer being called.
+func (w *World) OnEntityAdded(fn func(Entity)) func() {
+	return w.addedObservers.add(fn)
+}
+
+// Call the function every time an entity has been removed from the world.
+// Returns a function that stops the observer being called.
+func (w *World) OnEntityRemoved(fn func(Entity)) func() {
+	return w.removedObservers.add(fn)
+}
+
+// Call the function every time a tag is added to or removed from an entity.
+// Tags being removed because their entity has been removed are included.
+// Returns a function that stops the observer being called.
+func (w *World) OnTagChanged(fn func(e Entity, tag string, added bool)) func() {
+	return w.tagObservers.add(fn)
+}
+
+func (w *World) notifyTagChanged(e Entity, tag string, added bool) {
+	w.tagObservers.notify(func(fn func(Entity, string, bool)) {
+		fn(e, tag, added)
+	})
+}
+
+// Stop the entity from being added, if it is queued to be added, and drop any signals or tasks waiting for it.
+func (w *World) cancelQueuedAdd(e Entity) {
+	id := e.UUID()
+	w.queuedAdd = slices.DeleteFunc(w.queuedAdd, func(queued Entity) bool {
+		return queued.UUID() == id
+	})
+	delete(w.queuedAddWaitingSignals, id)
+	w.cancelBoundTasks(id)
+	w.Unparent(e)
+	w.freeSlot(id)
+}
@@ -0,0 +1,25 @@
+package ent
+
+import "testing"
+
+func TestAddAndRemoveInSameFrameCancelsBoundTasks(t *testing.T) {
+	w := NewWorld()
+	parent, e := &testEntity{}, &testEntity{}
+	w.AddNow(parent)
+	w.Add(e)
+	w.SetParent(e, parent)
+	ran := false
+	w.AfterFor(e, 1.5, func() { ran = true })
+	w.Remove(e)
+	w.Update(nil, 1)
+	w.Update(nil, 1)
+	if ran {
+		t.Fatal("timer bound to an entity that was never added should not run")
+	}
+	if w.Has(e) {
+		t.Fatal("entity should not have been added")
+	}
+	if len(w.children[parent.UUID()]) != 0 {
+		t.Fatal("entity should have been unparented")
+	}
+}
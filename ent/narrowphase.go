@@ -0,0 +1,314 @@
+package ent
+
+import (
+	"cmp"
+	"container/heap"
+	"math"
+	"slices"
+	"sync"
+)
+
+// Perform a collision physics update on the set of bodies, like StatelessCollisionPhysics, but spread the narrowphase over goroutines.
+// Candidate pairs are found by sweeping over the bodies' effect areas, then their shapes are collided on up to workers goroutines.
+// Corrections are then applied one pair at a time in the order StatelessCollisionPhysics checks pairs in.
+// Once a correction has moved a body, its later pairs are collided again using where it is now,
+// including pairs it has only moved into range of, so the collisions and final positions match StatelessCollisionPhysics.
+func ParallelCollisionPhysics(bodies []PhysicsBody, workers int) []Collision {
+	activeBodies, kinematicBodies := splitBodies(bodies)
+	pairs := broadphase(activeBodies, kinematicBodies)
+
+	results := make([]shapeCollision, len(pairs))
+	var wg sync.WaitGroup
+	workers = max(min(workers, len(pairs)), 1)
+	chunk := (len(pairs) + workers - 1) / workers
+	for start := 0; start < len(pairs); start += chunk {
+		end := min(start+chunk, len(pairs))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				results[i] = collideShapes(pairs[i].aShape, pairs[i].bShape)
+			}
+		}()
+	}
+	wg.Wait()
+
+	r := newCollisionResolver(activeBodies, kinematicBodies)
+	collisions := make([]Collision, 0)
+	next := 0
+	last := -1
+	for next < len(pairs) || len(r.extra) > 0 {
+		// Take whichever pair comes first out of the candidates and the pairs added by corrections
+		var pair bodyPair
+		var col shapeCollision
+		if next < len(pairs) && (len(r.extra) == 0 || pairs[next].order <= r.extra[0]) {
+			pair, col = pairs[next], results[next]
+			next++
+			if r.moved[pair.a] || (pair.bActive && r.moved[pair.b]) {
+				col = collideShapes(r.activeShapes[pair.a], r.shapeOf(pair.b, pair.bActive))
+			}
+		} else {
+			pair = r.pairWithOrder(heap.Pop(&r.extra).(int))
+			col = collideShapes(r.activeShapes[pair.a], r.shapeOf(pair.b, pair.bActive))
+		}
+		if pair.order == last {
+			continue
+		}
+		last = pair.order
+		if !col.collided {
+			continue
+		}
+		a := activeBodies[pair.a]
+		if pair.bActive {
+			collisions = append(collisions, respondActiveBodies(a, activeBodies[pair.b], col))
+			r.bodyMoved(pair.b, pair.order)
+		} else {
+			collisions = append(collisions, respondActiveAndKinematicBodies(a, kinematicBodies[pair.b], col))
+		}
+		r.bodyMoved(pair.a, pair.order)
+	}
+	return collisions
+}
+
+// Keeps track of which active bodies have been moved by corrections, and the pairs that need colliding again because of it.
+type collisionResolver struct {
+	activeBodies    []ActivePhysicsBody
+	activeShapes    []Shape
+	kinematicShapes []Shape
+	moved           []bool
+	// The orders of pairs that a moved body may now overlap with.
+	extra orderHeap
+	grid  *bodyGrid
+}
+
+func newCollisionResolver(activeBodies []ActivePhysicsBody, kinematicBodies []PhysicsBody) *collisionResolver {
+	r := &collisionResolver{
+		activeBodies:    activeBodies,
+		activeShapes:    make([]Shape, len(activeBodies)),
+		kinematicShapes: make([]Shape, len(kinematicBodies)),
+		moved:           make([]bool, len(activeBodies)),
+	}
+	for i, b := range activeBodies {
+		r.activeShapes[i] = b.Shape()
+	}
+	for i, b := range kinematicBodies {
+		r.kinematicShapes[i] = b.Shape()
+	}
+	r.grid = newBodyGrid(r.activeShapes)
+	return r
+}
+
+func (r *collisionResolver) shapeOf(index int, active bool) Shape {
+	if active {
+		return r.activeShapes[index]
+	}
+	return r.kinematicShapes[index]
+}
+
+// Note that the active body has been moved by the correction of the pair with the given order,
+// and queue its later pairs with bodies that it now overlaps the effect area of.
+func (r *collisionResolver) bodyMoved(index, after int) {
+	r.moved[index] = true
+	r.activeShapes[index] = r.activeBodies[index].Shape()
+	shape := r.activeShapes[index]
+	r.grid.move(index, shape)
+	numActive, numKinematic := len(r.activeShapes), len(r.kinematicShapes)
+	for j, other := range r.kinematicShapes {
+		if order := index*numKinematic + j; order > after && effectAreasOverlap(shape, other) {
+			heap.Push(&r.extra, order)
+		}
+	}
+	r.grid.near(index, func(j int) {
+		order := numActive*numKinematic + min(index, j)*numActive + max(index, j)
+		if order > after && effectAreasOverlap(shape, r.activeShapes[j]) {
+			heap.Push(&r.extra, order)
+		}
+	})
+}
+
+// A uniform grid of the active bodies' effect areas, used to find what a moved body might now overlap.
+type bodyGrid struct {
+	cellSize float64
+	cells    map[[2]int][]int
+	// The range of cells each body is in, as min x, min y, max x, max y.
+	bounds [][4]int
+	seen   []int
+	stamp  int
+}
+
+// Create a grid of the shapes, with cells a few times the size of an average shape.
+func newBodyGrid(shapes []Shape) *bodyGrid {
+	total := 0.0
+	for _, s := range shapes {
+		_, r := s.EffectArea()
+		total += r
+	}
+	g := &bodyGrid{
+		cellSize: max(4*total/float64(max(len(shapes), 1)), 1e-6),
+		cells:    make(map[[2]int][]int),
+		bounds:   make([][4]int, len(shapes)),
+		seen:     make([]int, len(shapes)),
+	}
+	for i, s := range shapes {
+		g.bounds[i] = g.boundsOf(s)
+		g.forCells(g.bounds[i], func(cell [2]int) {
+			g.cells[cell] = append(g.cells[cell], i)
+		})
+	}
+	return g
+}
+
+func (g *bodyGrid) boundsOf(s Shape) [4]int {
+	center, radius := s.EffectArea()
+	return [4]int{
+		int(math.Floor((center.X - radius) / g.cellSize)),
+		int(math.Floor((center.Y - radius) / g.cellSize)),
+		int(math.Floor((center.X + radius) / g.cellSize)),
+		int(math.Floor((center.Y + radius) / g.cellSize)),
+	}
+}
+
+func (g *bodyGrid) forCells(bounds [4]int, fn func([2]int)) {
+	for x := bounds[0]; x <= bounds[2]; x++ {
+		for y := bounds[1]; y <= bounds[3]; y++ {
+			fn([2]int{x, y})
+		}
+	}
+}
+
+// Move the body to the cells its new shape covers.
+func (g *bodyGrid) move(index int, s Shape) {
+	bounds := g.boundsOf(s)
+	if bounds == g.bounds[index] {
+		return
+	}
+	g.forCells(g.bounds[index], func(cell [2]int) {
+		g.cells[cell] = slices.DeleteFunc(g.cells[cell], func(i int) bool { return i == index })
+	})
+	g.bounds[index] = bounds
+	g.forCells(bounds, func(cell [2]int) {
+		g.cells[cell] = append(g.cells[cell], index)
+	})
+}
+
+// Call fn once with every other body that shares a cell with the body.
+func (g *bodyGrid) near(index int, fn func(int)) {
+	g.stamp++
+	g.seen[index] = g.stamp
+	g.forCells(g.bounds[index], func(cell [2]int) {
+		for _, j := range g.cells[cell] {
+			if g.seen[j] != g.stamp {
+				g.seen[j] = g.stamp
+				fn(j)
+			}
+		}
+	})
+}
+
+// Get the pair that comes at the given position in the serial checking order, see newBodyPair.
+func (r *collisionResolver) pairWithOrder(order int) bodyPair {
+	numActive, numKinematic := len(r.activeShapes), len(r.kinematicShapes)
+	if order < numActive*numKinematic {
+		return bodyPair{a: order / numKinematic, b: order % numKinematic, order: order}
+	}
+	rest := order - numActive*numKinematic
+	return bodyPair{a: rest / numActive, b: rest % numActive, bActive: true, order: order}
+}
+
+// Do the effect areas of the shapes overlap?
+func effectAreasOverlap(a, b Shape) bool {
+	p1, r1 := a.EffectArea()
+	p2, r2 := b.EffectArea()
+	return p1.To(p2).SqLen() <= (r1+r2)*(r1+r2)
+}
+
+// A min heap of pair orders.
+type orderHeap []int
+
+func (h orderHeap) Len() int           { return len(h) }
+func (h orderHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h orderHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *orderHeap) Push(x any) {
+	*h = append(*h, x.(int))
+}
+
+func (h *orderHeap) Pop() any {
+	old := *h
+	order := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return order
+}
+
+// Two bodies whose effect areas overlap.
+// a is an index into the active bodies, and b is an index into either the active or the kinematic bodies.
+type bodyPair struct {
+	a, b           int
+	bActive        bool
+	aShape, bShape Shape
+	order          int
+}
+
+type broadphaseEntry struct {
+	index      int
+	active     bool
+	shape      Shape
+	minX, maxX float64
+	minY, maxY float64
+}
+
+// Find the pairs of bodies whose effect areas overlap, where at least one body is active, using sweep and prune along the x axis.
+// The pairs are ordered the same way StatelessCollisionPhysics checks them: active and kinematic pairs first, then active and active pairs.
+func broadphase(activeBodies []ActivePhysicsBody, kinematicBodies []PhysicsBody) []bodyPair {
+	entries := make([]broadphaseEntry, 0, len(activeBodies)+len(kinematicBodies))
+	addEntry := func(index int, active bool, shape Shape) {
+		center, radius := shape.EffectArea()
+		entries = append(entries, broadphaseEntry{
+			index, active, shape,
+			center.X - radius, center.X + radius,
+			center.Y - radius, center.Y + radius,
+		})
+	}
+	for i, b := range activeBodies {
+		addEntry(i, true, b.Shape())
+	}
+	for i, b := range kinematicBodies {
+		addEntry(i, false, b.Shape())
+	}
+	slices.SortFunc(entries, func(a, b broadphaseEntry) int {
+		return cmp.Compare(a.minX, b.minX)
+	})
+
+	pairs := make([]bodyPair, 0)
+	for i, e1 := range entries {
+		for _, e2 := range entries[i+1:] {
+			if e2.minX > e1.maxX {
+				break
+			}
+			if !e1.active && !e2.active {
+				continue
+			}
+			if e2.minY > e1.maxY || e1.minY > e2.maxY {
+				continue
+			}
+			a, b := e1, e2
+			if !a.active || (b.active && b.index < a.index) {
+				a, b = b, a
+			}
+			pairs = append(pairs, newBodyPair(a, b, len(activeBodies), len(kinematicBodies)))
+		}
+	}
+	slices.SortFunc(pairs, func(a, b bodyPair) int {
+		return cmp.Compare(a.order, b.order)
+	})
+	return pairs
+}
+
+// Pair up an active body with another body, working out where the pair comes in the serial checking order.
+func newBodyPair(a, b broadphaseEntry, numActive, numKinematic int) bodyPair {
+	order := a.index*numKinematic + b.index
+	if b.active {
+		order = numActive*numKinematic + a.index*numActive + b.index
+	}
+	return bodyPair{a.index, b.index, b.active, a.shape, b.shape, order}
+}
@@ -0,0 +1,113 @@
+package ent
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/gopxl/pixel"
+)
+
+type testBody struct {
+	CoreEntity
+	WithActivePhysics
+	radius float64
+}
+
+func (b *testBody) Shape() Shape {
+	return Circle{Center: b.Position(), Radius: b.radius}
+}
+
+type testWall struct {
+	CoreEntity
+	WithStaticPhysics
+	shape Shape
+}
+
+func (w *testWall) Shape() Shape          { return w.shape }
+func (w *testWall) IsPhysicsActive() bool { return false }
+
+// Make a field of asteroid sized bodies, at about the density asteroids are spawned at, with a couple of walls.
+func collisionTestBodies(n int, seed int64) []PhysicsBody {
+	rng := rand.New(rand.NewSource(seed))
+	size := math.Sqrt(float64(n)) * 4
+	bodies := make([]PhysicsBody, 0, n+2)
+	for range n {
+		b := &testBody{radius: rng.Float64()*1.5 + 0.5}
+		b.SetPosition(pixel.V(rng.Float64(), rng.Float64()).Scaled(size))
+		b.SetVelocity(pixel.V(rng.Float64()-0.5, rng.Float64()-0.5))
+		bodies = append(bodies, b)
+	}
+	bodies = append(bodies,
+		&testWall{shape: Line{pixel.ZV, pixel.V(size, size)}},
+		&testWall{shape: MultiShape{[]Shape{
+			Circle{pixel.V(size/2, size/4), 3},
+			Circle{pixel.V(size/4, size/2), 2},
+		}}},
+	)
+	return bodies
+}
+
+func describeCollisions(bodies []PhysicsBody, cols []Collision) string {
+	index := make(map[PhysicsBody]int)
+	for i, b := range bodies {
+		index[b] = i
+	}
+	out := ""
+	for _, c := range cols {
+		out += fmt.Sprintf("%d-%d %v %v\n", index[c.Self], index[c.Other], c.Normal, c.Point)
+	}
+	for _, b := range bodies {
+		out += fmt.Sprintf("%v %v\n", b.Position(), b.Velocity())
+	}
+	return out
+}
+
+func TestParallelCollisionMatchesSerial(t *testing.T) {
+	for seed := range int64(5) {
+		serialBodies := collisionTestBodies(300, seed)
+		parallelBodies := collisionTestBodies(300, seed)
+		serialCols := StatelessCollisionPhysics(serialBodies)
+		if len(serialCols) < 50 {
+			t.Fatalf("seed %d: expected lots of collisions, got %d", seed, len(serialCols))
+		}
+		serial := describeCollisions(serialBodies, serialCols)
+		parallel := describeCollisions(parallelBodies, ParallelCollisionPhysics(parallelBodies, 4))
+		if serial != parallel {
+			t.Fatalf("seed %d: parallel collisions differ from serial:\nserial:\n%s\nparallel:\n%s", seed, serial, parallel)
+		}
+	}
+}
+
+func benchmarkCollision(b *testing.B, collide func([]PhysicsBody) []Collision) {
+	for _, n := range []int{500, 2000, 10000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for range b.N {
+				b.StopTimer()
+				bodies := collisionTestBodies(n, 1)
+				b.StartTimer()
+				collide(bodies)
+			}
+		})
+	}
+}
+
+// Every pair of bodies, without a broadphase.
+func BenchmarkCollisionSerial(b *testing.B) {
+	benchmarkCollision(b, StatelessCollisionPhysics)
+}
+
+// The broadphase with a single worker, so the parallel benchmark can be compared against it to measure the narrowphase speedup alone.
+func BenchmarkCollisionBroadphaseSerial(b *testing.B) {
+	benchmarkCollision(b, func(bodies []PhysicsBody) []Collision {
+		return ParallelCollisionPhysics(bodies, 1)
+	})
+}
+
+func BenchmarkCollisionParallel(b *testing.B) {
+	benchmarkCollision(b, func(bodies []PhysicsBody) []Collision {
+		return ParallelCollisionPhysics(bodies, runtime.NumCPU())
+	})
+}
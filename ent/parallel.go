@@ -0,0 +1,134 @@
+package ent
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gopxl/pixel/pixelgl"
+)
+
+// An Updater that only reads shared state in Update, and only changes itself.
+// When parallel updates are enabled, it may be updated at the same time as other ParallelSafe entities in its update layer.
+// The world it is given buffers Add, Remove, RefreshLayers, AddTags, RemoveTags, SetParent, Unparent, the Emit family, AskLater,
+// timers, tweens and scripts, and applies them at the end of the layer in update order.
+// It must not call Ask, AskAll, Subscribe, Unsubscribe, or Topic with a new topic name.
+// Entities with children are always updated serially.
+//
+// Messages emitted while updating in parallel are delivered at the end of the layer, and HandleMessage is only ever called from
+// the game loop, never from a worker. So later entities in the layer do not see messages from earlier ones while they update,
+// as they would serially. The results only match serial updates if entities in the layer do not depend on messages from the same layer.
+type ParallelSafe interface {
+	Updater
+	ParallelSafe()
+}
+
+// World mutations made by one entity during a parallel update, waiting to be applied.
+type commandBuffer struct {
+	commands []func(*World)
+	applied  bool
+}
+
+func (c *commandBuffer) record(fn func(*World)) {
+	c.commands = append(c.commands, fn)
+}
+
+// Should mutations be recorded rather than applied straight away?
+// Once the buffer has been applied, views act on the world directly, so callbacks that captured them keep working.
+func (w *World) buffering() bool {
+	return w.commands != nil && !w.commands.applied
+}
+
+// Set the number of goroutines used to update ParallelSafe entities.
+// With 1 or fewer (the default), every entity is updated serially.
+// With more than 1, the results do not depend on the number of workers, as buffered mutations are always applied in update order.
+// They match serial updates as long as the ParallelSafe entities keep to its rules.
+func (w *World) SetUpdateWorkers(n int) {
+	w.updateWorkers = n
+}
+
+// Set the number of goroutines used to collide shapes during the physics step, see ParallelCollisionPhysics.
+// With 1 or fewer (the default), StatelessCollisionPhysics is used instead.
+func (w *World) SetCollisionWorkers(n int) {
+	w.collisionWorkers = n
+}
+
+// Update all Updaters that are not visited by their parent, running runs of ParallelSafe entities in the same layer concurrently.
+// Entities are checked against a snapshot of the update order, so entities removed by an earlier entity are skipped and
+// entities added during the update wait until next update, however many workers there are.
+func (es *World) updateAll(win *pixelgl.Window, dt float64) {
+	batch := make([]Updater, 0)
+	for _, e := range slices.Collect(es.orderedByUpdate.All()) {
+		if !es.Has(e) || isVisitedByParent[Updater](es, e) {
+			continue
+		}
+		if es.updateWorkers > 1 && es.canUpdateInParallel(e) {
+			if len(batch) > 0 && batch[0].UpdateLayer() != e.UpdateLayer() {
+				es.updateParallel(win, batch, dt)
+				batch = batch[:0]
+			}
+			batch = append(batch, e)
+			continue
+		}
+		es.updateParallel(win, batch, dt)
+		batch = batch[:0]
+		es.updateTree(win, e, dt)
+	}
+	es.updateParallel(win, batch, dt)
+	es.activeEntity = 0
+}
+
+func (es *World) canUpdateInParallel(e Updater) bool {
+	_, ok := e.(ParallelSafe)
+	return ok && len(es.children[e.UUID()]) == 0
+}
+
+// Update the entities across the workers, each with its own buffering view of the world, then apply what they buffered in order,
+// as if each entity had been updated serially at that point.
+func (es *World) updateParallel(win *pixelgl.Window, batch []Updater, dt float64) {
+	if len(batch) == 0 {
+		return
+	}
+	buffers := make([]commandBuffer, len(batch))
+	durations := make([]time.Duration, len(batch))
+	// Entities in the batch may move time zones, so work out everyone's dt before any of them update
+	dts := make([]float64, len(batch))
+	for i, e := range batch {
+		dts[i] = es.dtFor(e, dt)
+	}
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range min(es.updateWorkers, len(batch)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1)) - 1
+				if i >= len(batch) {
+					return
+				}
+				view := &World{worldState: es.worldState, commands: &buffers[i], activeEntity: batch[i].UUID()}
+				start := time.Now()
+				batch[i].Update(win, view, dts[i])
+				durations[i] = time.Since(start)
+			}
+		}()
+	}
+	wg.Wait()
+	for i, e := range batch {
+		buffers[i].applied = true
+		// Updated serially, an entity removed by an earlier one would not have been updated, so drop what it did
+		if !es.Has(e) {
+			continue
+		}
+		if es.profiler != nil {
+			es.profiler.addEntity(SectionUpdate, e, e.UpdateLayer(), durations[i])
+		}
+		es.activeEntity = e.UUID()
+		for _, command := range buffers[i].commands {
+			command(es)
+		}
+	}
+	es.activeEntity = 0
+}
@@ -0,0 +1,133 @@
+package ent
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/gopxl/pixel/pixelgl"
+)
+
+type testHit struct{}
+
+// A ParallelSafe entity that exercises each kind of buffered mutation.
+type parallelTestEntity struct {
+	CoreEntity
+	WithUpdate
+	id      int
+	updates int
+	elapsed float64
+	hits    int
+	ticks   int
+	target  Ref[*parallelTestEntity]
+}
+
+func (p *parallelTestEntity) ParallelSafe() {}
+
+func (p *parallelTestEntity) HandleMessage(_ *World, msg any) {
+	if _, ok := msg.(testHit); ok {
+		p.hits++
+	}
+}
+
+func (p *parallelTestEntity) Update(_ *pixelgl.Window, w *World, dt float64) {
+	p.updates++
+	p.elapsed += dt
+	switch {
+	case p.updates%3 == 0 && p.id < 1000:
+		w.Add(&parallelTestEntity{id: p.id*1000 + p.updates})
+	case p.updates%4 == 0:
+		w.Remove(p)
+	}
+	if target, ok := p.target.Get(w); ok {
+		EmitDirectly(w, testHit{}, target)
+		if p.updates == 2 && p.id%5 == 0 {
+			w.RemoveNow(target)
+		}
+	}
+	if p.updates == 1 {
+		w.AfterFor(p, 0.5, func() { p.ticks++ })
+	}
+}
+
+// Build a world of parallel test entities, each targeting the next, and run it for some frames.
+func runParallelTestWorld(workers int) string {
+	w := NewWorld()
+	w.SetUpdateWorkers(workers)
+	entities := make([]*parallelTestEntity, 64)
+	for i := range entities {
+		entities[i] = &parallelTestEntity{id: i}
+		w.AddNow(entities[i])
+	}
+	for i, e := range entities[:len(entities)-1] {
+		e.target = RefTo(entities[i+1])
+	}
+	for range 10 {
+		w.Update(nil, 0.25)
+	}
+	states := make([]string, 0)
+	for e := range w.allEntities.All() {
+		p := e.(*parallelTestEntity)
+		states = append(states, fmt.Sprintf("%v:%d:%d:%g:%d:%d", p.UUID(), p.id, p.updates, p.elapsed, p.hits, p.ticks))
+	}
+	slices.Sort(states)
+	return fmt.Sprint(states)
+}
+
+func TestParallelUpdatesMatchSerial(t *testing.T) {
+	serial := runParallelTestWorld(1)
+	for _, workers := range []int{2, 8} {
+		if parallel := runParallelTestWorld(workers); parallel != serial {
+			t.Errorf("%d workers gave a different world to serial updates:\nserial:   %s\nparallel: %s", workers, serial, parallel)
+		}
+	}
+}
+
+// A ParallelSafe entity that hits its target, and records how many hits it had taken when it updated.
+type parallelObserver struct {
+	CoreEntity
+	WithUpdate
+	hits   int
+	seen   []int
+	target Ref[*parallelObserver]
+}
+
+func (p *parallelObserver) ParallelSafe() {}
+
+func (p *parallelObserver) HandleMessage(_ *World, msg any) {
+	if _, ok := msg.(testHit); ok {
+		p.hits++
+	}
+}
+
+func (p *parallelObserver) Update(_ *pixelgl.Window, w *World, dt float64) {
+	p.seen = append(p.seen, p.hits)
+	if target, ok := p.target.Get(w); ok {
+		EmitDirectly(w, testHit{}, target)
+	}
+}
+
+// Messages emitted during a parallel update are only delivered once the layer has finished,
+// so unlike serial updates, later entities in the layer do not see them while updating.
+func TestParallelMessagesArriveAfterLayer(t *testing.T) {
+	run := func(workers int) *parallelObserver {
+		w := NewWorld()
+		w.SetUpdateWorkers(workers)
+		first, second := &parallelObserver{}, &parallelObserver{}
+		w.AddNow(first, second)
+		first.target = RefTo(second)
+		w.Update(nil, 1)
+		w.Update(nil, 1)
+		return second
+	}
+	serial, parallel := run(1), run(2)
+	if !slices.Equal(serial.seen, []int{1, 2}) {
+		t.Errorf("serially, the hit should arrive before the target updates, saw %v", serial.seen)
+	}
+	if !slices.Equal(parallel.seen, []int{0, 1}) {
+		t.Errorf("in parallel, the hit should arrive after the layer, saw %v", parallel.seen)
+	}
+	if serial.hits != 2 || parallel.hits != 2 {
+		t.Errorf("every hit should be delivered by the end of the frame, got %d serially and %d in parallel", serial.hits, parallel.hits)
+	}
+}
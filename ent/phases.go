@@ -0,0 +1,108 @@
+package ent
+
+import (
+	"github.com/gopxl/pixel/pixelgl"
+)
+
+// A named step of World.Update (or World.Draw, for PhasePreDraw).
+// Phases run in the order they are declared.
+type Phase int
+
+const (
+	// Before any Update methods are called.
+	PhasePreUpdate Phase = iota
+	// Updaters, then timers, tweens and scripts, then queued adds, removes and messages.
+	PhaseUpdate
+	// Just before physics bodies are moved and collisions are resolved.
+	PhasePrePhysics
+	// Just after collision handlers have been called.
+	PhasePostPhysics
+	// The end of World.Update, when everything else has settled for the frame.
+	PhaseLateUpdate
+	// The start of World.Draw, after every Drawer's PreDraw has been called.
+	PhasePreDraw
+	phaseCount
+)
+
+var phaseNames = [phaseCount]string{"PreUpdate", "Update", "PrePhysics", "PostPhysics", "LateUpdate", "PreDraw"}
+
+func (p Phase) String() string {
+	if p < 0 || p >= phaseCount {
+		return "Phase(?)"
+	}
+	return phaseNames[p]
+}
+
+// An entity that is called in PhasePreUpdate.
+type PreUpdater interface {
+	EntityUUIDer
+	PreUpdate(win *pixelgl.Window, world *World, dt float64)
+}
+
+// An entity that is called in PhasePrePhysics.
+type PrePhysicsUpdater interface {
+	EntityUUIDer
+	PrePhysicsUpdate(win *pixelgl.Window, world *World, dt float64)
+}
+
+// An entity that is called in PhasePostPhysics.
+type PostPhysicsUpdater interface {
+	EntityUUIDer
+	PostPhysicsUpdate(win *pixelgl.Window, world *World, dt float64)
+}
+
+// An entity that is called in PhaseLateUpdate.
+type LateUpdater interface {
+	EntityUUIDer
+	LateUpdate(win *pixelgl.Window, world *World, dt float64)
+}
+
+type system struct {
+	id  uint64
+	run func(win *pixelgl.Window, w *World, dt float64)
+}
+
+// Register a system that runs fn for every entity that matches the query and is a T, once per frame in the given phase.
+// Systems run after the phase's entity hooks, in the order they were registered.
+// dt is always 0 in PhasePreDraw.
+// Call the returned function to unregister the system.
+func RegisterSystem[T any](w *World, phase Phase, q *Query, fn func(win *pixelgl.Window, w *World, e T, dt float64)) func() {
+	w.systemSeq++
+	id := w.systemSeq
+	w.systems[phase] = append(w.systems[phase], system{id, func(win *pixelgl.Window, w *World, dt float64) {
+		for e := range Find[T](w, q) {
+			if id, ok := any(e).(EntityUUIDer); ok {
+				w.activeEntity = id.UUID()
+			}
+			fn(win, w, e, dt)
+		}
+		w.activeEntity = 0
+	}})
+	return func() {
+		for i, s := range w.systems[phase] {
+			if s.id == id {
+				w.systems[phase] = append(w.systems[phase][:i:i], w.systems[phase][i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Call each entity hook in the index, then run the systems registered for the phase.
+func runPhase[T EntityUUIDer](w *World, phase Phase, index *Index[T], win *pixelgl.Window, dt float64, call func(T, float64)) {
+	section := ProfileSection(phase.String())
+	defer w.profileSection(section)()
+	for e := range index.All() {
+		w.activeEntity = e.UUID()
+		w.profileEntity(section, e, 0, func() { call(e, w.dtFor(e, dt)) })
+	}
+	w.activeEntity = 0
+	w.runSystems(phase, win, dt)
+}
+
+// Run the systems registered for the phase.
+func (w *World) runSystems(phase Phase, win *pixelgl.Window, dt float64) {
+	for _, s := range w.systems[phase] {
+		s.run(win, w, dt)
+	}
+}
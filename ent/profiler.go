@@ -0,0 +1,266 @@
+package ent
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"time"
+)
+
+// A named part of World.Update or World.Draw that the profiler times.
+type ProfileSection string
+
+const (
+	SectionPreUpdate          ProfileSection = "PreUpdate"
+	SectionUpdate             ProfileSection = "Update"
+	SectionTasks              ProfileSection = "Tasks"
+	SectionQueued             ProfileSection = "Queued"
+	SectionMessages           ProfileSection = "Messages"
+	SectionPrePhysics         ProfileSection = "PrePhysics"
+	SectionIntegrate          ProfileSection = "Integrate"
+	SectionCollide            ProfileSection = "Collide"
+	SectionCollisionListeners ProfileSection = "CollisionListeners"
+	SectionPostPhysics        ProfileSection = "PostPhysics"
+	SectionLateUpdate         ProfileSection = "LateUpdate"
+	SectionPreDraw            ProfileSection = "PreDraw"
+	SectionDraw               ProfileSection = "Draw"
+)
+
+// The default number of frames that profiler averages are taken over.
+const DefaultProfileWindow = 120
+
+// Records where a world's frame time goes, see World.SetProfiler.
+// Timings are kept for a rolling window of frames, from which averages are taken, and which can be exported as a Chrome trace.
+type Profiler struct {
+	window  int
+	frames  []frameProfile
+	next    int
+	current *frameProfile
+	started time.Time
+}
+
+// Timings of entities of one concrete type in one layer, during one section.
+type profileKey struct {
+	section ProfileSection
+	typ     reflect.Type
+	layer   int
+}
+
+type frameProfile struct {
+	frame       uint64
+	start       time.Time
+	end         time.Time
+	sections    map[ProfileSection]time.Duration
+	entities    map[profileKey]time.Duration
+	spans       []profileSpan
+	numEntities int
+	collisions  int
+	messages    int
+}
+
+type profileSpan struct {
+	section ProfileSection
+	start   time.Time
+	dur     time.Duration
+}
+
+// The average cost of a frame, over the profiler's window.
+type ProfileStats struct {
+	// The number of frames the averages were taken over.
+	Frames   int
+	Frame    time.Duration
+	Sections map[ProfileSection]time.Duration
+	// Keyed by section then by concrete type name.
+	Types map[ProfileSection]map[string]time.Duration
+	// Keyed by section then by update or draw layer.
+	Layers     map[ProfileSection]map[int]time.Duration
+	Entities   float64
+	Collisions float64
+	Messages   float64
+}
+
+// Create a profiler that averages over the given number of frames.
+// If window is not positive, DefaultProfileWindow is used.
+func NewProfiler(window int) *Profiler {
+	if window <= 0 {
+		window = DefaultProfileWindow
+	}
+	return &Profiler{
+		window:  window,
+		frames:  make([]frameProfile, 0, window),
+		started: time.Now(),
+	}
+}
+
+// Set the profiler used to time each frame, or nil to stop profiling.
+func (w *World) SetProfiler(p *Profiler) {
+	w.profiler = p
+}
+
+// Get the profiler set with SetProfiler, if any.
+func (w *World) Profiler() *Profiler {
+	return w.profiler
+}
+
+// Finish the previous frame, and start recording the given one.
+func (p *Profiler) beginFrame(frame uint64) {
+	p.endFrame()
+	p.current = &frameProfile{
+		frame:    frame,
+		start:    time.Now(),
+		sections: make(map[ProfileSection]time.Duration),
+		entities: make(map[profileKey]time.Duration),
+	}
+}
+
+func (p *Profiler) endFrame() {
+	if p.current == nil {
+		return
+	}
+	p.current.end = time.Now()
+	if len(p.frames) < p.window {
+		p.frames = append(p.frames, *p.current)
+	} else {
+		p.frames[p.next] = *p.current
+		p.next = (p.next + 1) % p.window
+	}
+	p.current = nil
+}
+
+// Add the time spent since start to the section.
+func (p *Profiler) addSection(section ProfileSection, start time.Time) {
+	if p.current == nil {
+		return
+	}
+	dur := time.Since(start)
+	p.current.sections[section] += dur
+	p.current.spans = append(p.current.spans, profileSpan{section, start, dur})
+}
+
+// Add time spent by one entity during the section.
+func (p *Profiler) addEntity(section ProfileSection, e any, layer int, dur time.Duration) {
+	if p.current == nil {
+		return
+	}
+	p.current.entities[profileKey{section, reflect.TypeOf(e), layer}] += dur
+}
+
+func (p *Profiler) countMessage() {
+	if p.current != nil {
+		p.current.messages++
+	}
+}
+
+// Start timing a section of the world's frame, returning the function to call when it ends.
+func (w *World) profileSection(section ProfileSection) func() {
+	if w.profiler == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { w.profiler.addSection(section, start) }
+}
+
+// Call fn, timing it against the entity's type and layer if profiling.
+func (w *World) profileEntity(section ProfileSection, e any, layer int, fn func()) {
+	if w.profiler == nil {
+		fn()
+		return
+	}
+	start := time.Now()
+	fn()
+	w.profiler.addEntity(section, e, layer, time.Since(start))
+}
+
+// Get the average cost of a frame over the completed frames in the window.
+func (p *Profiler) Averages() ProfileStats {
+	stats := ProfileStats{
+		Frames:   len(p.frames),
+		Sections: make(map[ProfileSection]time.Duration),
+		Types:    make(map[ProfileSection]map[string]time.Duration),
+		Layers:   make(map[ProfileSection]map[int]time.Duration),
+	}
+	if stats.Frames == 0 {
+		return stats
+	}
+	n := time.Duration(stats.Frames)
+	for _, f := range p.frames {
+		stats.Frame += f.end.Sub(f.start) / n
+		for section, dur := range f.sections {
+			stats.Sections[section] += dur / n
+		}
+		for key, dur := range f.entities {
+			if stats.Types[key.section] == nil {
+				stats.Types[key.section] = make(map[string]time.Duration)
+				stats.Layers[key.section] = make(map[int]time.Duration)
+			}
+			stats.Types[key.section][key.typ.String()] += dur / n
+			stats.Layers[key.section][key.layer] += dur / n
+		}
+		stats.Entities += float64(f.numEntities) / float64(n)
+		stats.Collisions += float64(f.collisions) / float64(n)
+		stats.Messages += float64(f.messages) / float64(n)
+	}
+	return stats
+}
+
+// Forget all recorded frames.
+func (p *Profiler) Clear() {
+	p.frames = p.frames[:0]
+	p.next = 0
+}
+
+type chromeTraceEvent struct {
+	Name  string         `json:"name"`
+	Phase string         `json:"ph"`
+	Time  float64        `json:"ts"`
+	Dur   float64        `json:"dur,omitempty"`
+	PID   int            `json:"pid"`
+	TID   int            `json:"tid"`
+	Args  map[string]any `json:"args,omitempty"`
+}
+
+// Write the frames in the window as Chrome trace event JSON, which can be opened in chrome://tracing or Perfetto.
+func (p *Profiler) WriteChromeTrace(out io.Writer) error {
+	micros := func(t time.Time) float64 {
+		return float64(t.Sub(p.started).Nanoseconds()) / 1000
+	}
+	events := make([]chromeTraceEvent, 0)
+	for i := range p.frames {
+		f := p.frames[(p.next+i)%len(p.frames)]
+		events = append(events, chromeTraceEvent{
+			Name:  "Frame",
+			Phase: "X",
+			Time:  micros(f.start),
+			Dur:   float64(f.end.Sub(f.start).Nanoseconds()) / 1000,
+			PID:   1,
+			TID:   1,
+			Args:  map[string]any{"frame": f.frame},
+		})
+		for _, s := range f.spans {
+			events = append(events, chromeTraceEvent{
+				Name:  string(s.section),
+				Phase: "X",
+				Time:  micros(s.start),
+				Dur:   float64(s.dur.Nanoseconds()) / 1000,
+				PID:   1,
+				TID:   1,
+			})
+		}
+		events = append(events, chromeTraceEvent{
+			Name:  "Counts",
+			Phase: "C",
+			Time:  micros(f.start),
+			PID:   1,
+			TID:   1,
+			Args: map[string]any{
+				"entities":   f.numEntities,
+				"collisions": f.collisions,
+				"messages":   f.messages,
+			},
+		})
+	}
+	return json.NewEncoder(out).Encode(map[string]any{
+		"traceEvents":     events,
+		"displayTimeUnit": "ms",
+	})
+}
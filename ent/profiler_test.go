@@ -0,0 +1,102 @@
+package ent
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Record a frame with the given costs, as World.Update would.
+func profileTestFrame(p *Profiler, frame uint64, update time.Duration, entities int) {
+	p.beginFrame(frame)
+	p.current.sections[SectionUpdate] = update
+	p.current.entities[profileKey{SectionUpdate, reflect.TypeFor[*testEntity](), 2}] = update / 2
+	p.current.numEntities = entities
+	p.current.collisions = 1
+}
+
+func TestProfilerAveragesOverWindow(t *testing.T) {
+	p := NewProfiler(3)
+	if stats := p.Averages(); stats.Frames != 0 {
+		t.Fatalf("a new profiler should have no frames, got %d", stats.Frames)
+	}
+	for i := 1; i <= 5; i++ {
+		profileTestFrame(p, uint64(i), time.Duration(i)*3*time.Millisecond, i*10)
+	}
+	// The fifth frame is still in progress, so the window holds frames 2 to 4
+	stats := p.Averages()
+	if stats.Frames != 3 {
+		t.Fatalf("got %d frames, want 3", stats.Frames)
+	}
+	if got := stats.Sections[SectionUpdate]; got != 9*time.Millisecond {
+		t.Errorf("update section averages %v, want 9ms", got)
+	}
+	if got := stats.Types[SectionUpdate]["*ent.testEntity"]; got != 4500*time.Microsecond {
+		t.Errorf("entity type averages %v, want 4.5ms", got)
+	}
+	if got := stats.Layers[SectionUpdate][2]; got != 4500*time.Microsecond {
+		t.Errorf("layer averages %v, want 4.5ms", got)
+	}
+	if stats.Entities != 30 || stats.Collisions != 1 {
+		t.Errorf("got %v entities and %v collisions, want 30 and 1", stats.Entities, stats.Collisions)
+	}
+
+	p.beginFrame(6)
+	if got := p.Averages().Sections[SectionUpdate]; got != 12*time.Millisecond {
+		t.Errorf("after the window moves on, update section averages %v, want 12ms", got)
+	}
+	p.Clear()
+	if stats := p.Averages(); stats.Frames != 0 {
+		t.Fatalf("a cleared profiler should have no frames, got %d", stats.Frames)
+	}
+}
+
+func TestProfilerWritesFramesInOrder(t *testing.T) {
+	p := NewProfiler(3)
+	for i := 1; i <= 6; i++ {
+		profileTestFrame(p, uint64(i), time.Millisecond, 1)
+	}
+	var out bytes.Buffer
+	if err := p.WriteChromeTrace(&out); err != nil {
+		t.Fatal(err)
+	}
+	var trace struct {
+		TraceEvents []chromeTraceEvent `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &trace); err != nil {
+		t.Fatal(err)
+	}
+	frames := make([]float64, 0)
+	for _, e := range trace.TraceEvents {
+		if e.Name == "Frame" {
+			frames = append(frames, e.Args["frame"].(float64))
+		}
+	}
+	if !slices.Equal(frames, []float64{3, 4, 5}) {
+		t.Fatalf("got frames %v, want the window oldest first", frames)
+	}
+}
+
+func TestWorldProfilesSections(t *testing.T) {
+	w := NewWorld()
+	w.SetProfiler(NewProfiler(0))
+	w.AddNow(&unscaledTestEntity{}, &testEntity{})
+	for range 3 {
+		w.Update(nil, 1)
+	}
+	stats := w.Profiler().Averages()
+	if stats.Frames != 2 || stats.Entities != 2 {
+		t.Fatalf("got %d frames with %v entities, want 2 with 2", stats.Frames, stats.Entities)
+	}
+	for _, section := range []ProfileSection{SectionUpdate, SectionTasks, SectionQueued, SectionMessages, SectionIntegrate, SectionCollide} {
+		if _, ok := stats.Sections[section]; !ok {
+			t.Errorf("section %s was not recorded", section)
+		}
+	}
+	if _, ok := stats.Types[SectionUpdate]["*ent.unscaledTestEntity"]; !ok {
+		t.Errorf("updater was not recorded, got %v", stats.Types)
+	}
+}
@@ -0,0 +1,317 @@
+package ent
+
+import (
+	"iter"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gopxl/pixel"
+)
+
+// A description of a set of entities in a world, built up by chaining filters.
+// Each filter returns a new query, so queries can be shared and extended safely.
+// Run a query with Find or FindFirst.
+type Query struct {
+	allOf    []string
+	anyOf    []string
+	noneOf   []string
+	within   *spatialCircle
+	inRect   *pixel.Rect
+	sortBy   func(a, b Entity) int
+	nearest  *pixel.Vec
+	limit    int
+	cached   bool
+	cacheKey string
+}
+
+type spatialCircle struct {
+	center pixel.Vec
+	radius float64
+}
+
+// Create a query that matches every entity.
+func NewQuery() *Query {
+	return &Query{}
+}
+
+func (q *Query) clone() *Query {
+	c := *q
+	c.allOf = slices.Clone(q.allOf)
+	c.anyOf = slices.Clone(q.anyOf)
+	c.noneOf = slices.Clone(q.noneOf)
+	return &c
+}
+
+// Only match entities that have all of the tags.
+func (q *Query) AllOf(tags ...string) *Query {
+	c := q.clone()
+	c.allOf = append(c.allOf, tags...)
+	c.updateCacheKey()
+	return c
+}
+
+// Only match entities that have at least one of the tags.
+func (q *Query) AnyOf(tags ...string) *Query {
+	c := q.clone()
+	c.anyOf = append(c.anyOf, tags...)
+	c.updateCacheKey()
+	return c
+}
+
+// Only match entities that have none of the tags.
+func (q *Query) NoneOf(tags ...string) *Query {
+	c := q.clone()
+	c.noneOf = append(c.noneOf, tags...)
+	c.updateCacheKey()
+	return c
+}
+
+// Only match entities that are Positioners within the radius of the center.
+func (q *Query) Within(center pixel.Vec, radius float64) *Query {
+	c := q.clone()
+	c.within = &spatialCircle{center, radius}
+	return c
+}
+
+// Only match entities that are Positioners inside the rectangle.
+func (q *Query) InRect(r pixel.Rect) *Query {
+	c := q.clone()
+	c.inRect = &r
+	return c
+}
+
+// Order the matched entities using the comparison function.
+func (q *Query) SortBy(cmp func(a, b Entity) int) *Query {
+	c := q.clone()
+	c.sortBy = cmp
+	c.nearest = nil
+	return c
+}
+
+// Order the matched entities by their distance to the point, closest first.
+// Entities that are not Positioners come last.
+func (q *Query) NearestTo(p pixel.Vec) *Query {
+	c := q.clone()
+	c.nearest = &p
+	c.sortBy = nil
+	return c
+}
+
+// Only match up to n entities.
+func (q *Query) Limit(n int) *Query {
+	c := q.clone()
+	c.limit = n
+	return c
+}
+
+// Keep the entities matching this query's tags and type in an index on the world, which is updated as entities and tags change.
+// Spatial filters, sorting and limits are still applied each time the query is run.
+// Use for queries that are run often, such as every frame.
+func (q *Query) Cached() *Query {
+	c := q.clone()
+	c.cached = true
+	return c
+}
+
+func (q *Query) updateCacheKey() {
+	part := func(tags []string) string {
+		sorted := slices.Clone(tags)
+		slices.Sort(sorted)
+		return strings.Join(slices.Compact(sorted), ",")
+	}
+	q.cacheKey = part(q.allOf) + "|" + part(q.anyOf) + "|" + part(q.noneOf)
+}
+
+// Does the entity have the right tags to match the query?
+func (q *Query) matchesTags(w *World, e Entity) bool {
+	hasTag := func(tag string) bool {
+		index, ok := w.byTags[tag]
+		return ok && index.Has(e)
+	}
+	for _, tag := range q.allOf {
+		if !hasTag(tag) {
+			return false
+		}
+	}
+	if len(q.anyOf) > 0 && !slices.ContainsFunc(q.anyOf, hasTag) {
+		return false
+	}
+	return !slices.ContainsFunc(q.noneOf, hasTag)
+}
+
+// Is the entity in the right place to match the query?
+func (q *Query) matchesSpace(e Entity) bool {
+	if q.within == nil && q.inRect == nil {
+		return true
+	}
+	p, ok := e.(Positioner)
+	if !ok {
+		return false
+	}
+	pos := p.Position()
+	if q.within != nil && pos.To(q.within.center).SqLen() > q.within.radius*q.within.radius {
+		return false
+	}
+	return q.inRect == nil || q.inRect.Contains(pos)
+}
+
+// Get the entities that could match the query, using the smallest tag index available.
+func (q *Query) candidates(w *World) iter.Seq[Entity] {
+	if len(q.allOf) > 0 {
+		var smallest *Index[Entity]
+		for _, tag := range q.allOf {
+			index, ok := w.byTags[tag]
+			if !ok {
+				return func(yield func(Entity) bool) {}
+			}
+			if smallest == nil || index.Len() < smallest.Len() {
+				smallest = index
+			}
+		}
+		return smallest.All()
+	}
+	if len(q.anyOf) > 0 {
+		return func(yield func(Entity) bool) {
+			seen := make(map[EntityUUID]struct{})
+			for _, tag := range q.anyOf {
+				for e := range w.WithTag(tag) {
+					if _, ok := seen[e.UUID()]; ok {
+						continue
+					}
+					seen[e.UUID()] = struct{}{}
+					if !yield(e) {
+						return
+					}
+				}
+			}
+		}
+	}
+	return w.allEntities.All()
+}
+
+// Run the query against the world, yielding the matching entities that are Ts.
+func Find[T any](w *World, q *Query) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var matches iter.Seq[Entity]
+		if c := cachedQueryFor[T](w, q); c != nil {
+			matches = c.matches.All()
+		} else {
+			matches = func(yield func(Entity) bool) {
+				for e := range q.candidates(w) {
+					if _, ok := e.(T); ok && q.matchesTags(w, e) && !yield(e) {
+						return
+					}
+				}
+			}
+		}
+		count := 0
+		limitReached := func() bool {
+			return q.limit > 0 && count >= q.limit
+		}
+		if q.sortBy == nil && q.nearest == nil {
+			for e := range matches {
+				if !q.matchesSpace(e) {
+					continue
+				}
+				if limitReached() || !yield(e.(T)) {
+					return
+				}
+				count++
+			}
+			return
+		}
+		sorted := make([]Entity, 0)
+		for e := range matches {
+			if q.matchesSpace(e) {
+				sorted = append(sorted, e)
+			}
+		}
+		slices.SortStableFunc(sorted, q.compare)
+		for _, e := range sorted {
+			if limitReached() || !yield(e.(T)) {
+				return
+			}
+			count++
+		}
+	}
+}
+
+// Run the query against the world, returning the first matching entity that is a T.
+func FindFirst[T any](w *World, q *Query) (T, bool) {
+	return First(Find[T](w, q))
+}
+
+func (q *Query) compare(a, b Entity) int {
+	if q.sortBy != nil {
+		return q.sortBy(a, b)
+	}
+	distance := func(e Entity) float64 {
+		p, ok := e.(Positioner)
+		if !ok {
+			return math.Inf(1)
+		}
+		return p.Position().To(*q.nearest).SqLen()
+	}
+	da, db := distance(a), distance(b)
+	switch {
+	case da < db:
+		return -1
+	case da > db:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type queryCacheKey struct {
+	tags string
+	typ  reflect.Type
+}
+
+// The entities matching a cached query's tags and type, kept up to date by world observers.
+type queryCache struct {
+	matches *Index[Entity]
+}
+
+// Get the cache for the query's tags and type, creating and filling it if it does not exist yet.
+// Returns nil if the query is not cached, or if the cache does not exist yet and the world is buffering a parallel update.
+func cachedQueryFor[T any](w *World, q *Query) *queryCache {
+	if !q.cached {
+		return nil
+	}
+	key := queryCacheKey{q.cacheKey, reflect.TypeFor[T]()}
+	if c, ok := w.queryCaches[key]; ok {
+		return c
+	}
+	if w.buffering() {
+		// Building a cache changes the world, so leave it to the next serial query
+		return nil
+	}
+	c := &queryCache{
+		matches: NewUnorderedIndex[Entity](),
+	}
+	isT := func(e Entity) bool {
+		_, ok := e.(T)
+		return ok
+	}
+	consider := func(e Entity) {
+		if !w.Has(e) {
+			return
+		}
+		if isT(e) && q.matchesTags(w, e) {
+			c.matches.Add(e)
+		} else {
+			c.matches.Remove(e)
+		}
+	}
+	for e := range q.candidates(w) {
+		consider(e)
+	}
+	w.OnEntityAdded(consider)
+	w.OnTagChanged(func(e Entity, _ string, _ bool) { consider(e) })
+	w.OnEntityRemoved(func(e Entity) { c.matches.Remove(e) })
+	w.queryCaches[key] = c
+	return c
+}
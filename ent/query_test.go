@@ -0,0 +1,138 @@
+package ent
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gopxl/pixel"
+)
+
+type testPositioned struct {
+	CoreEntity
+	WithTransform
+}
+
+func findIDs[T Entity](w *World, q *Query) []EntityUUID {
+	ids := make([]EntityUUID, 0)
+	for e := range Find[T](w, q) {
+		ids = append(ids, e.UUID())
+	}
+	return ids
+}
+
+func TestQueryTagFilters(t *testing.T) {
+	w := NewWorld()
+	red, bigRed, blue, plain := &testEntity{}, &testEntity{}, &testEntity{}, &testEntity{}
+	w.AddNow(red, bigRed, blue, plain)
+	w.AddTags(red, "red")
+	w.AddTags(bigRed, "red", "big")
+	w.AddTags(blue, "blue")
+	tests := []struct {
+		name  string
+		query *Query
+		want  []Entity
+	}{
+		{"all", NewQuery(), []Entity{red, bigRed, blue, plain}},
+		{"all of one", NewQuery().AllOf("red"), []Entity{red, bigRed}},
+		{"all of two", NewQuery().AllOf("red", "big"), []Entity{bigRed}},
+		{"all of a missing tag", NewQuery().AllOf("red", "green"), []Entity{}},
+		{"any of", NewQuery().AnyOf("red", "blue"), []Entity{red, bigRed, blue}},
+		{"none of", NewQuery().NoneOf("red"), []Entity{blue, plain}},
+		{"all of and none of", NewQuery().AllOf("red").NoneOf("big"), []Entity{red}},
+		{"any of and none of", NewQuery().AnyOf("blue", "big").NoneOf("red"), []Entity{blue}},
+	}
+	for _, tt := range tests {
+		want := make([]EntityUUID, len(tt.want))
+		for i, e := range tt.want {
+			want[i] = e.UUID()
+		}
+		slices.Sort(want)
+		for _, cached := range []bool{false, true} {
+			q := tt.query
+			if cached {
+				q = q.Cached()
+			}
+			got := findIDs[Entity](w, q)
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Errorf("%s (cached %v): got %v, want %v", tt.name, cached, got, want)
+			}
+		}
+	}
+}
+
+func TestQueryNearestTo(t *testing.T) {
+	w := NewWorld()
+	far, near, middle, nowhere := &testPositioned{}, &testPositioned{}, &testPositioned{}, &testEntity{}
+	far.SetPosition(pixel.V(5, 0))
+	near.SetPosition(pixel.V(0, 1))
+	middle.SetPosition(pixel.V(-3, 0))
+	w.AddNow(far, nowhere, near, middle)
+	tests := []struct {
+		name  string
+		query *Query
+		want  []Entity
+	}{
+		{"nearest first, then those without a position", NewQuery().NearestTo(pixel.ZV), []Entity{near, middle, far, nowhere}},
+		{"limited", NewQuery().NearestTo(pixel.ZV).Limit(2), []Entity{near, middle}},
+		{"from another point", NewQuery().NearestTo(pixel.V(6, 0)).Limit(1), []Entity{far}},
+		{"within", NewQuery().Within(pixel.ZV, 3).NearestTo(pixel.ZV), []Entity{near, middle}},
+	}
+	for _, tt := range tests {
+		want := make([]EntityUUID, len(tt.want))
+		for i, e := range tt.want {
+			want[i] = e.UUID()
+		}
+		if got := findIDs[Entity](w, tt.query); !slices.Equal(got, want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, want)
+		}
+	}
+}
+
+func TestCachedQueryFollowsChanges(t *testing.T) {
+	w := NewWorld()
+	e, other := &testPositioned{}, &testEntity{}
+	w.AddNow(e, other)
+	red := NewQuery().AllOf("red").Cached()
+	visible := NewQuery().NoneOf("hidden").Cached()
+	check := func(step string, q *Query, want ...Entity) {
+		t.Helper()
+		wantIDs := make([]EntityUUID, len(want))
+		for i, e := range want {
+			wantIDs[i] = e.UUID()
+		}
+		slices.Sort(wantIDs)
+		got := findIDs[Entity](w, q)
+		slices.Sort(got)
+		if !slices.Equal(got, wantIDs) {
+			t.Errorf("%s: got %v, want %v", step, got, wantIDs)
+		}
+	}
+	check("before tagging", red)
+	check("before hiding", visible, e, other)
+	if got := findIDs[*testPositioned](w, visible); len(got) != 1 {
+		t.Errorf("the same query for another type should be cached separately, got %v", got)
+	}
+
+	w.AddTags(e, "red")
+	check("after AddTags", red, e)
+	w.AddTags(other, "hidden")
+	check("after hiding", visible, e)
+	w.RemoveTags(e, "red")
+	check("after RemoveTags", red)
+	w.RemoveTags(other, "hidden")
+	check("after unhiding", visible, e, other)
+
+	w.AddTags(e, "red")
+	w.RemoveNow(e)
+	check("after RemoveNow", red)
+	check("after RemoveNow", visible, other)
+
+	added := &testEntity{}
+	w.Add(added)
+	w.AddTags(added, "red")
+	check("before queued add", red)
+	w.Update(nil, 1)
+	check("after queued add", red, added)
+	check("after queued add", visible, other, added)
+}
@@ -0,0 +1,67 @@
+package ent
+
+// A typed reference to an entity, resolved through the world when it is needed.
+// Unlike holding the entity itself, a Ref notices when the entity has been removed.
+// Refs serialize as the entity's UUID, so they can be stored in messages and save files.
+// The zero value refers to nothing.
+type Ref[T any] struct {
+	id          EntityUUID
+	cached      T
+	cachedWorld *worldState
+	cachedStamp uint64
+}
+
+// Create a reference to the entity.
+// The entity must already be in the world, or queued to be added, so that it has a UUID, otherwise this panics.
+func RefTo[T EntityUUIDer](e T) Ref[T] {
+	return Ref[T]{id: handleOf(e, "make a reference to")}
+}
+
+// Create a reference to the entity with the given UUID, which is expected to be a T.
+func RefToUUID[T any](id EntityUUID) Ref[T] {
+	return Ref[T]{id: id}
+}
+
+// Get the UUID of the referenced entity.
+func (r Ref[T]) UUID() EntityUUID {
+	return r.id
+}
+
+// Does this reference point at nothing?
+func (r Ref[T]) IsZero() bool {
+	return r.id == 0
+}
+
+// Get the referenced entity, if it is in the world and is a T.
+// The result is cached until the world next removes an entity.
+func (r *Ref[T]) Get(w *World) (T, bool) {
+	if r.id == 0 {
+		return *new(T), false
+	}
+	if r.cachedWorld == w.worldState && r.cachedStamp == w.slotsFreed {
+		return r.cached, true
+	}
+	t, ok := OneOfType[T](w.WithUUID(r.id))
+	if !ok {
+		r.cachedWorld = nil
+		return *new(T), false
+	}
+	r.cached, r.cachedWorld, r.cachedStamp = t, w.worldState, w.slotsFreed
+	return t, true
+}
+
+// Has the referenced entity been removed from the world (or never been added)?
+// Entities that are queued to be added are not counted as removed.
+// Always false for the zero Ref.
+func (r Ref[T]) Removed(w *World) bool {
+	return r.id != 0 && !w.HasOrQueued(r.id)
+}
+
+func (r Ref[T]) MarshalText() ([]byte, error) {
+	return r.id.MarshalText()
+}
+
+func (r *Ref[T]) UnmarshalText(text []byte) error {
+	*r = Ref[T]{}
+	return r.id.UnmarshalText(text)
+}
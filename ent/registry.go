@@ -0,0 +1,69 @@
+package ent
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// An index that the world keeps in sync as entities are added and removed.
+type worldIndex interface {
+	AddUntyped(item any) bool
+	RemoveUntyped(item any) bool
+	RefreshUntyped(item any) bool
+}
+
+// Create an index on the world of every entity that is a T, kept in sync as entities are added and removed.
+// Entities already in the world are added straight away.
+// The index is ordered by orderFunc (highest first), or by the order entities were added if it is nil.
+// If an index for T has already been registered with the same orderFunc, that index is returned instead.
+// Panics if it was registered with a different orderFunc, as the existing index would not be in the order asked for.
+func RegisterIndex[T EntityUUIDer](w *World, orderFunc func(T) int) *Index[T] {
+	typ := reflect.TypeFor[T]()
+	if existing, ok := w.indexesByType[typ]; ok {
+		if existing.order != funcPointer(orderFunc) {
+			panic(fmt.Sprintf("an index for %v has already been registered with a different order function", typ))
+		}
+		return existing.index.(*Index[T])
+	}
+	var index *Index[T]
+	if orderFunc == nil {
+		index = NewUnorderedIndex[T]()
+	} else {
+		index = NewOrderedIndex(orderFunc)
+	}
+	if w.allEntities != nil {
+		for e := range w.allEntities.All() {
+			index.AddUntyped(e)
+		}
+	}
+	w.indexes = append(w.indexes, index)
+	w.indexesByType[typ] = registeredIndex{index, funcPointer(orderFunc)}
+	return index
+}
+
+// An index registered with RegisterIndex, and the order function it was registered with.
+type registeredIndex struct {
+	index any
+	order uintptr
+}
+
+// Get the code pointer of the function, or 0 if it is nil, so functions can be compared.
+// Closures made by the same function literal compare equal, even if they capture different variables.
+func funcPointer(fn any) uintptr {
+	v := reflect.ValueOf(fn)
+	if v.IsNil() {
+		return 0
+	}
+	return v.Pointer()
+}
+
+// Get every entity in the world that is a T.
+// If an index for T has been registered with RegisterIndex, it is used (and its order is kept),
+// otherwise all entities are checked.
+func IndexOf[T EntityUUIDer](w *World) iter.Seq[T] {
+	if registered, ok := w.indexesByType[reflect.TypeFor[T]()]; ok {
+		return registered.index.(*Index[T]).All()
+	}
+	return OfType[T](w.allEntities.All())
+}
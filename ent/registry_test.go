@@ -0,0 +1,19 @@
+package ent
+
+import "testing"
+
+func TestRegisterIndexTwice(t *testing.T) {
+	w := NewWorld()
+	if RegisterIndex(w, Updater.UpdateLayer) != w.orderedByUpdate {
+		t.Fatal("registering with the same order function should return the existing index")
+	}
+	if RegisterIndex[Entity](w, nil) != w.allEntities {
+		t.Fatal("registering an unordered index again should return the existing index")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("registering with a different order function should panic")
+		}
+	}()
+	RegisterIndex(w, func(u Updater) int { return -u.UpdateLayer() })
+}
@@ -0,0 +1,191 @@
+package ent
+
+import (
+	"iter"
+	"slices"
+)
+
+// A condition that a script waits on before it continues.
+type Wait interface {
+	// Called once per update, with the world and the update's dt, until it returns true.
+	Done(w *World, dt float64) bool
+}
+
+// A scripted behaviour, written as an iterator.
+// Each Wait that is yielded is waited on before the script continues.
+// The script should return when yield returns false, as that means it has been stopped.
+type Script = iter.Seq[Wait]
+
+// Wait for the given number of seconds of world time.
+func WaitSeconds(seconds float64) Wait {
+	return &secondsWait{remaining: seconds}
+}
+
+type secondsWait struct {
+	remaining float64
+}
+
+func (s *secondsWait) Done(_ *World, dt float64) bool {
+	s.remaining -= dt
+	return s.remaining <= 0
+}
+
+// Wait for the given number of updates.
+func WaitFrames(frames int) Wait {
+	return &framesWait{remaining: frames}
+}
+
+type framesWait struct {
+	remaining int
+}
+
+func (f *framesWait) Done(*World, float64) bool {
+	f.remaining--
+	return f.remaining <= 0
+}
+
+// Wait until the condition is true. It is checked once per update.
+func WaitUntil(cond func() bool) Wait {
+	return untilWait(cond)
+}
+
+type untilWait func() bool
+
+func (u untilWait) Done(*World, float64) bool {
+	return u()
+}
+
+// Wait until the entity running the script is sent a message of type T.
+// Scripts not bound to an entity will never receive messages.
+// The message can be read with Message once the script continues.
+func WaitMessage[T any]() *MessageWait[T] {
+	return &MessageWait[T]{}
+}
+
+// A wait for a message of type T, created with WaitMessage.
+type MessageWait[T any] struct {
+	msg      T
+	received bool
+}
+
+func (m *MessageWait[T]) Done(*World, float64) bool {
+	return m.received
+}
+
+// Get the message that was received.
+func (m *MessageWait[T]) Message() T {
+	return m.msg
+}
+
+func (m *MessageWait[T]) offer(msg any) {
+	if m.received {
+		return
+	}
+	if msgT, ok := msg.(T); ok {
+		m.msg = msgT
+		m.received = true
+	}
+}
+
+type messageOfferer interface {
+	offer(msg any)
+}
+
+// A running script, created by World.RunScript or World.RunScriptFor.
+type ScriptHandle struct {
+	script  Script
+	next    func() (Wait, bool)
+	stop    func()
+	current Wait
+	owner   EntityUUID
+	running bool
+	done    bool
+}
+
+// Stop the script. It will not continue past its current wait.
+func (s *ScriptHandle) Cancel() {
+	s.done = true
+	if s.stop != nil && !s.running {
+		s.stop()
+	}
+}
+
+// Is the script still running?
+func (s *ScriptHandle) Active() bool {
+	return !s.done
+}
+
+// Run the script, starting on the next update.
+func (w *World) RunScript(s Script) *ScriptHandle {
+	return w.runScript(s, 0)
+}
+
+// Like RunScript, but the script is stopped when the entity is removed from the world.
+// The script will also be able to wait for messages sent to the entity.
+func (w *World) RunScriptFor(e EntityUUIDer, s Script) *ScriptHandle {
+	return w.runScript(s, handleOf(e, "run a script for"))
+}
+
+func (w *World) runScript(s Script, owner EntityUUID) *ScriptHandle {
+	h := &ScriptHandle{script: s, owner: owner}
+	if w.buffering() {
+		w.commands.record(func(w *World) { w.startScript(h) })
+	} else {
+		w.startScript(h)
+	}
+	return h
+}
+
+func (w *World) startScript(h *ScriptHandle) {
+	w.scripts = append(w.scripts, h)
+	if h.owner != 0 {
+		w.bindToEntity(h.owner, h)
+	}
+}
+
+// Continue the script until it yields a wait that is not yet done, or it ends.
+func (s *ScriptHandle) resume(w *World, dt float64) {
+	if s.next == nil {
+		s.next, s.stop = iter.Pull(s.script)
+	} else if !s.current.Done(w, dt) {
+		return
+	}
+	s.running = true
+	wait, ok := s.next()
+	s.running = false
+	if !ok || s.done {
+		s.Cancel()
+		return
+	}
+	s.current = wait
+}
+
+// Resume all running scripts, forgetting any that have ended.
+// Scripts started while this is happening will start next update.
+// The scripts stay in w.scripts throughout, so messages sent by one script can be received by another.
+func (w *World) updateScripts(dt float64) {
+	running := w.scripts[:len(w.scripts):len(w.scripts)]
+	for _, s := range running {
+		if s.done {
+			continue
+		}
+		w.activeEntity = s.owner
+		s.resume(w, dt)
+	}
+	w.activeEntity = 0
+	w.scripts = slices.DeleteFunc(w.scripts, func(s *ScriptHandle) bool {
+		return s.done
+	})
+}
+
+// Pass a message sent to the entity on to any of its scripts that are waiting for messages.
+func (w *World) offerMessageToScripts(id EntityUUID, msg any) {
+	for _, s := range w.scripts {
+		if s.owner != id || s.done {
+			continue
+		}
+		if mw, ok := s.current.(messageOfferer); ok {
+			mw.offer(msg)
+		}
+	}
+}
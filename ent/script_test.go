@@ -0,0 +1,31 @@
+package ent
+
+import "testing"
+
+type testPing struct{}
+
+func TestScriptReceivesMessageEmittedByAnotherScript(t *testing.T) {
+	w := NewWorld()
+	a, b := &testEntity{}, &testEntity{}
+	w.AddNow(a, b)
+	received := false
+	w.RunScriptFor(b, func(yield func(Wait) bool) {
+		if !yield(WaitMessage[testPing]()) {
+			return
+		}
+		received = true
+	})
+	// Started after b's script so b is already waiting when a sends the message
+	w.RunScriptFor(a, func(yield func(Wait) bool) {
+		if !yield(WaitFrames(1)) {
+			return
+		}
+		EmitDirectly(w, testPing{}, b)
+	})
+	for range 3 {
+		w.Update(nil, 1)
+	}
+	if !received {
+		t.Fatal("script b should have received the message script a sent")
+	}
+}
@@ -10,6 +10,8 @@ import (
 type Shape interface {
 	shape()
 	EffectArea() (pixel.Vec, float64)
+	// Is the point inside the shape?
+	Contains(p pixel.Vec) bool
 }
 
 func (Circle) shape()     {}
@@ -26,6 +28,10 @@ func (c Circle) EffectArea() (pixel.Vec, float64) {
 	return c.Center, c.Radius
 }
 
+func (c Circle) Contains(p pixel.Vec) bool {
+	return c.Center.To(p).SqLen() <= c.Radius*c.Radius
+}
+
 type MultiShape struct {
 	Shapes []Shape
 }
@@ -47,6 +53,15 @@ func (ms MultiShape) EffectArea() (pixel.Vec, float64) {
 	return rect.Center(), rect.Size().Len() / 2
 }
 
+func (ms MultiShape) Contains(p pixel.Vec) bool {
+	for _, s := range ms.Shapes {
+		if s.Contains(p) {
+			return true
+		}
+	}
+	return false
+}
+
 // A line shape.
 type Line struct {
 	A pixel.Vec
@@ -57,6 +72,11 @@ func (l Line) EffectArea() (pixel.Vec, float64) {
 	return l.A.Add(l.B).Scaled(0.5), l.A.To(l.B).Len() / 2
 }
 
+// A line has no area, so contains nothing.
+func (l Line) Contains(p pixel.Vec) bool {
+	return false
+}
+
 // A collision of two shapes.
 type shapeCollision struct {
 	collided bool
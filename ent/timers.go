@@ -0,0 +1,161 @@
+package ent
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+)
+
+// A callback scheduled to run on world time, created by World.After, World.Every, or their entity bound variants.
+type Timer struct {
+	at       float64
+	interval float64
+	repeat   bool
+	fn       func()
+	owner    EntityUUID
+	seq      uint64
+	done     bool
+}
+
+// Stop the timer from running again.
+func (t *Timer) Cancel() {
+	t.done = true
+}
+
+// Is the timer still waiting to run (again)?
+func (t *Timer) Active() bool {
+	return !t.done
+}
+
+// Get the world time that this timer will next run at.
+func (t *Timer) NextRun() float64 {
+	return t.at
+}
+
+// Get the world time, which is the sum of all dts passed to Update.
+func (w *World) Time() float64 {
+	return w.time
+}
+
+// Run the function once, after the given number of seconds of world time.
+func (w *World) After(seconds float64, fn func()) *Timer {
+	return w.schedule(seconds, false, fn, 0)
+}
+
+// Run the function every time the given number of seconds of world time passes.
+// If the interval is not positive, the function will run once per update.
+func (w *World) Every(seconds float64, fn func()) *Timer {
+	return w.schedule(seconds, true, fn, 0)
+}
+
+// Like After, but the timer is cancelled when the entity is removed from the world.
+func (w *World) AfterFor(e EntityUUIDer, seconds float64, fn func()) *Timer {
+	return w.schedule(seconds, false, fn, handleOf(e, "start a timer for"))
+}
+
+// Like Every, but the timer is cancelled when the entity is removed from the world.
+func (w *World) EveryFor(e EntityUUIDer, seconds float64, fn func()) *Timer {
+	return w.schedule(seconds, true, fn, handleOf(e, "start a timer for"))
+}
+
+func (w *World) schedule(seconds float64, repeat bool, fn func(), owner EntityUUID) *Timer {
+	t := &Timer{
+		interval: seconds,
+		repeat:   repeat,
+		fn:       fn,
+		owner:    owner,
+	}
+	if w.buffering() {
+		w.commands.record(func(w *World) { w.startTimer(t) })
+	} else {
+		w.startTimer(t)
+	}
+	return t
+}
+
+func (w *World) startTimer(t *Timer) {
+	t.seq = w.timerSeq
+	w.timerSeq++
+	t.at = w.nextRunTime(w.time, t.interval)
+	heap.Push(&w.timers, t)
+	if t.owner != 0 {
+		w.bindToEntity(t.owner, t)
+	}
+}
+
+// Run the timers that are due, in the order they are due (then the order they were created).
+// Repeating timers that have fallen behind will run multiple times to catch up.
+func (w *World) runTimers() {
+	for len(w.timers) > 0 && w.timers[0].at <= w.time {
+		t := heap.Pop(&w.timers).(*Timer)
+		if t.done {
+			continue
+		}
+		if t.repeat {
+			t.at = w.nextRunTime(t.at, t.interval)
+			heap.Push(&w.timers, t)
+		} else {
+			t.done = true
+		}
+		w.activeEntity = t.owner
+		t.fn()
+	}
+	w.activeEntity = 0
+}
+
+// Get the time that something scheduled at from with the interval should run.
+// Non-positive intervals run on the next update.
+func (w *World) nextRunTime(from, interval float64) float64 {
+	if interval <= 0 {
+		return math.Nextafter(w.time, math.Inf(1))
+	}
+	return from + interval
+}
+
+// Something that runs on world time and should stop when an entity is removed.
+type boundTask interface {
+	Cancel()
+	Active() bool
+}
+
+// Cancel the task when the entity is removed from the world.
+func (w *World) bindToEntity(id EntityUUID, task boundTask) {
+	tasks := slices.DeleteFunc(w.boundTasks[id], func(t boundTask) bool {
+		return !t.Active()
+	})
+	w.boundTasks[id] = append(tasks, task)
+}
+
+// Cancel all tasks bound to the entity.
+func (w *World) cancelBoundTasks(id EntityUUID) {
+	for _, t := range w.boundTasks[id] {
+		t.Cancel()
+	}
+	delete(w.boundTasks, id)
+}
+
+// A min heap of timers, ordered by run time then creation order.
+type timerHeap []*Timer
+
+func (h timerHeap) Len() int { return len(h) }
+
+func (h timerHeap) Less(i, j int) bool {
+	if h[i].at != h[j].at {
+		return h[i].at < h[j].at
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h timerHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *timerHeap) Push(x any) {
+	*h = append(*h, x.(*Timer))
+}
+
+func (h *timerHeap) Pop() any {
+	old := *h
+	t := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return t
+}
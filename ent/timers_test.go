@@ -0,0 +1,108 @@
+package ent
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTimersRunInOrder(t *testing.T) {
+	w := NewWorld()
+	ran := make([]string, 0)
+	w.After(3, func() { ran = append(ran, "3s") })
+	w.After(1, func() { ran = append(ran, "1s first") })
+	w.After(2, func() { ran = append(ran, "2s") })
+	w.After(1, func() { ran = append(ran, "1s second") })
+	w.After(4, func() { ran = append(ran, "4s") })
+	w.Update(nil, 3)
+	want := []string{"1s first", "1s second", "2s", "3s"}
+	if !slices.Equal(ran, want) {
+		t.Fatalf("got %v, want %v", ran, want)
+	}
+}
+
+func TestEveryCatchesUp(t *testing.T) {
+	w := NewWorld()
+	times := make([]float64, 0)
+	timer := w.Every(1, func() { times = append(times, w.Time()) })
+	w.Update(nil, 3.5)
+	if len(times) != 3 {
+		t.Fatalf("should run once for each interval that passed, ran %d times", len(times))
+	}
+	if timer.NextRun() != 4 {
+		t.Fatalf("should next run at 4, not %v", timer.NextRun())
+	}
+	w.Update(nil, 0.5)
+	if len(times) != 4 {
+		t.Fatalf("should run again at 4, ran %d times", len(times))
+	}
+}
+
+func TestCancelTimer(t *testing.T) {
+	w := NewWorld()
+	ranAfter, ranEvery := false, 0
+	after := w.After(1, func() { ranAfter = true })
+	var every *Timer
+	every = w.Every(1, func() {
+		ranEvery++
+		every.Cancel()
+	})
+	after.Cancel()
+	w.Update(nil, 5)
+	if ranAfter || after.Active() {
+		t.Fatal("cancelled timer should not run")
+	}
+	if ranEvery != 1 || every.Active() {
+		t.Fatalf("timer cancelled while running should not run again, ran %d times", ranEvery)
+	}
+}
+
+func TestTimersCancelledWithOwner(t *testing.T) {
+	for _, remove := range []struct {
+		name string
+		fn   func(w *World, e Entity)
+	}{
+		{"Remove", func(w *World, e Entity) { w.Remove(e) }},
+		{"RemoveNow", func(w *World, e Entity) { w.RemoveNow(e) }},
+	} {
+		t.Run(remove.name, func(t *testing.T) {
+			w := NewWorld()
+			e := &testEntity{}
+			w.AddNow(e)
+			afterRan, everyRan := false, 0
+			after := w.AfterFor(e, 2, func() { afterRan = true })
+			every := w.EveryFor(e, 1, func() { everyRan++ })
+			w.Update(nil, 1)
+			remove.fn(w, e)
+			// Queued removals are applied after this update's timers, none of which are due yet
+			w.Update(nil, 0.5)
+			w.Update(nil, 5)
+			if afterRan || after.Active() {
+				t.Fatal("AfterFor timer should be cancelled with its owner")
+			}
+			if everyRan != 1 || every.Active() {
+				t.Fatalf("EveryFor timer should be cancelled with its owner, ran %d times", everyRan)
+			}
+		})
+	}
+}
+
+func TestTimersUseScaledTime(t *testing.T) {
+	w := NewWorld()
+	ran := false
+	w.After(1, func() { ran = true })
+	w.SetTimeScale(0.5)
+	w.Update(nil, 1)
+	if ran {
+		t.Fatal("only half a second of world time has passed")
+	}
+	w.Pause()
+	w.Update(nil, 10)
+	if ran {
+		t.Fatal("timers should not run while paused")
+	}
+	w.Resume()
+	w.Update(nil, 1)
+	if !ran || w.Time() != 1 {
+		t.Fatalf("timer should have run at world time 1, ran %v at %v", ran, w.Time())
+	}
+}
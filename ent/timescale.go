@@ -0,0 +1,141 @@
+package ent
+
+import (
+	"github.com/gopxl/pixel"
+	"github.com/gopxl/pixel/pixelgl"
+)
+
+// An entity whose update hooks are always given unscaled time, such as a HUD. It also ignores time zones.
+// If it is an Updater, it keeps updating while the world is paused, but its children do not.
+type Unscaled interface {
+	UnscaledTime()
+}
+
+// Set how fast time passes in the world, where 1 is normal speed and 0 is paused.
+// The dt passed to Update is multiplied by this before anything else is updated, including physics, timers and tweens.
+func (w *World) SetTimeScale(scale float64) {
+	w.timeScale = max(scale, 0)
+	w.pendingSteps = 0
+}
+
+// Get how fast time passes in the world, see SetTimeScale.
+func (w *World) TimeScale() float64 {
+	return w.timeScale
+}
+
+// Is the time scale 0?
+func (w *World) Paused() bool {
+	return w.timeScale == 0
+}
+
+// Set the time scale to 0, remembering the current one for Resume.
+// Pausing while already paused does nothing.
+func (w *World) Pause() {
+	if w.Paused() {
+		return
+	}
+	w.pausedTimeScale = w.timeScale
+	w.SetTimeScale(0)
+}
+
+// Go back to the time scale from before Pause, or normal speed if it was paused another way.
+func (w *World) Resume() {
+	if !w.Paused() {
+		return
+	}
+	scale := w.pausedTimeScale
+	if scale == 0 {
+		scale = 1
+	}
+	w.SetTimeScale(scale)
+}
+
+// While paused, update the world at normal speed for the next given number of calls to Update.
+func (w *World) StepFrames(n int) {
+	w.pendingSteps += max(n, 0)
+}
+
+// Get how many more calls to Update will step the world, see StepFrames.
+func (w *World) PendingSteps() int {
+	return w.pendingSteps
+}
+
+// Get the time interval the entity should be updated with, given the world's scaled dt.
+// Entities with a position are slowed down or sped up by the time zones they are inside.
+func (w *World) dtFor(e any, dt float64) float64 {
+	if _, ok := e.(Unscaled); ok {
+		return w.unscaledDt
+	}
+	if _, ok := e.(IgnoresTimeZones); ok || w.timeZones.Len() == 0 {
+		return dt
+	}
+	if t, ok := e.(Positioner); ok {
+		return dt * w.TimeScaleAt(t.Position())
+	}
+	return dt
+}
+
+// Update only the Unscaled Updaters, then add and remove queued entities and deliver queued messages.
+// Paused frames are profiled like any other, but keep the number of the last frame that was not paused.
+func (es *World) updatePaused(win *pixelgl.Window, dt float64) {
+	if es.profiler != nil {
+		es.profiler.beginFrame(es.frame)
+	}
+	end := es.profileSection(SectionUpdate)
+	for e := range es.orderedByUpdate.All() {
+		if _, ok := e.(Unscaled); !ok {
+			continue
+		}
+		es.activeEntity = e.UUID()
+		es.profileEntity(SectionUpdate, e, e.UpdateLayer(), func() { e.Update(win, es, dt) })
+	}
+	es.activeEntity = 0
+	end()
+	end = es.profileSection(SectionQueued)
+	es.applyQueued()
+	end()
+	end = es.profileSection(SectionMessages)
+	es.deliverQueuedMessages()
+	end()
+	if es.profiler != nil && es.profiler.current != nil {
+		es.profiler.current.numEntities = es.allEntities.Len()
+	}
+}
+
+// An entity that changes how fast time passes for entities whose position is inside its shape.
+type TimeZone interface {
+	Entity
+	// Get the area the zone affects.
+	ZoneShape() Shape
+	// Get how fast time passes inside the zone, where 1 is normal speed and 0 is stopped.
+	ZoneTimeScale() float64
+	// Where zones overlap, only those with the highest priority apply, and their time scales multiply.
+	ZonePriority() int
+}
+
+// An entity that is not slowed down or sped up by time zones, but is still affected by the world's time scale.
+type IgnoresTimeZones interface {
+	IgnoreTimeZones()
+}
+
+// Get how fast time passes at the point because of the time zones it is inside, on top of the world's time scale.
+// Only the zones with the highest priority apply, and their time scales are multiplied together.
+func (w *World) TimeScaleAt(p pixel.Vec) float64 {
+	scale := 1.0
+	found := false
+	priority := 0
+	for z := range w.timeZones.All() {
+		if !z.ZoneShape().Contains(p) {
+			continue
+		}
+		switch {
+		case !found || z.ZonePriority() > priority:
+			scale = z.ZoneTimeScale()
+			priority = z.ZonePriority()
+			found = true
+		case z.ZonePriority() == priority:
+			scale *= z.ZoneTimeScale()
+		}
+	}
+	return max(scale, 0)
+}
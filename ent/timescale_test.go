@@ -0,0 +1,127 @@
+package ent
+
+import (
+	"testing"
+
+	"github.com/gopxl/pixel"
+	"github.com/gopxl/pixel/pixelgl"
+)
+
+type unscaledTestEntity struct {
+	CoreEntity
+	updates int
+}
+
+func (e *unscaledTestEntity) UnscaledTime() {}
+
+func (e *unscaledTestEntity) UpdateLayer() int { return 0 }
+
+func (e *unscaledTestEntity) Update(win *pixelgl.Window, w *World, dt float64) {
+	e.updates++
+}
+
+func TestPausedFramesAreProfiled(t *testing.T) {
+	w := NewWorld()
+	e := &unscaledTestEntity{}
+	w.AddNow(e)
+	w.SetProfiler(NewProfiler(0))
+	w.Pause()
+	for range 3 {
+		w.Update(nil, 1)
+	}
+	if e.updates != 3 {
+		t.Fatalf("unscaled entity should update while paused, got %d updates", e.updates)
+	}
+	stats := w.Profiler().Averages()
+	if stats.Frames < 2 {
+		t.Fatalf("paused frames should be recorded, got %d", stats.Frames)
+	}
+	if _, ok := stats.Types[SectionUpdate]["*ent.unscaledTestEntity"]; !ok {
+		t.Fatalf("unscaled entity should be profiled in the update section, got %v", stats.Types)
+	}
+	if stats.Entities != 1 {
+		t.Fatalf("paused frames should count entities, got %v", stats.Entities)
+	}
+}
+
+type testTimeZone struct {
+	CoreEntity
+	scale    float64
+	priority int
+}
+
+func (z *testTimeZone) ZoneShape() Shape       { return Circle{Radius: 10} }
+func (z *testTimeZone) ZoneTimeScale() float64 { return z.scale }
+func (z *testTimeZone) ZonePriority() int      { return z.priority }
+
+func TestTimeScaleAt(t *testing.T) {
+	tests := []struct {
+		name  string
+		zones []*testTimeZone
+		want  float64
+	}{
+		{"no zones", nil, 1},
+		{"one zone", []*testTimeZone{{scale: 0.5}}, 0.5},
+		{"equal priorities multiply", []*testTimeZone{{scale: 0.5}, {scale: 0.25}}, 0.125},
+		{"higher priority overrides", []*testTimeZone{{scale: 0.5}, {scale: 2, priority: 1}, {scale: 0.25}}, 2},
+		{"highest priorities multiply", []*testTimeZone{{scale: 0.1, priority: -1}, {scale: 0.5, priority: 2}, {scale: 3, priority: 2}}, 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWorld()
+			for _, z := range tt.zones {
+				w.AddNow(z)
+			}
+			if got := w.TimeScaleAt(pixel.ZV); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if got := w.TimeScaleAt(pixel.V(100, 0)); got != 1 {
+				t.Errorf("outside every zone got %v, want 1", got)
+			}
+		})
+	}
+}
+
+func TestTimeScaleCommand(t *testing.T) {
+	w := NewWorld()
+	ctx := CommandContext{World: w}
+	if _, err := RunCommand(ctx, "timescale", "0.5"); err != nil {
+		t.Fatal(err)
+	}
+	if w.TimeScale() != 0.5 {
+		t.Fatalf("time scale should be 0.5, is %g", w.TimeScale())
+	}
+	for _, bad := range []string{"-1", "fast"} {
+		if _, err := RunCommand(ctx, "timescale", bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+	if _, err := RunCommand(CommandContext{}, "timescale", "1"); err == nil {
+		t.Error("expected an error without a world")
+	}
+}
+
+func TestStepCommand(t *testing.T) {
+	w := NewWorld()
+	ctx := CommandContext{World: w}
+	if _, err := RunCommand(ctx, "step", "2"); err != nil {
+		t.Fatal(err)
+	}
+	if !w.Paused() || w.PendingSteps() != 2 {
+		t.Fatalf("step should pause with 2 steps pending, got paused %v with %d", w.Paused(), w.PendingSteps())
+	}
+	for range 3 {
+		w.Update(nil, 1)
+	}
+	if w.Time() != 2 || w.PendingSteps() != 0 {
+		t.Fatalf("world should have stepped 2 frames, time is %g with %d steps pending", w.Time(), w.PendingSteps())
+	}
+	for _, bad := range []string{"0", "many"} {
+		if _, err := RunCommand(ctx, "step", bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+	if _, err := RunCommand(CommandContext{}, "step"); err == nil {
+		t.Error("expected an error without a world")
+	}
+}
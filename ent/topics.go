@@ -0,0 +1,60 @@
+package ent
+
+import (
+	"slices"
+	"strings"
+)
+
+// Get the shared bus for the named topic, creating it if it does not exist yet.
+// Topic names are made of segments separated by dots, for example "asteroid.destroyed".
+// A "*" segment is a wildcard that matches any single segment, or when it is the last segment, any number of remaining segments.
+// Entities subscribed to a wildcard topic, such as "asteroid.*", receive every message emitted on the topics it matches.
+func (w *World) Topic(name string) *Bus {
+	if b, ok := w.topics[name]; ok {
+		return b
+	}
+	b := &Bus{topic: name}
+	w.topics[name] = b
+	return b
+}
+
+// Get the name of the topic this bus was created for, or an empty string if it is not a world topic.
+func (b *Bus) Topic() string {
+	return b.topic
+}
+
+// Get all wildcard topics, other than the topic itself, that match the topic.
+// The topics are returned in name order so delivery is deterministic.
+func (w *World) wildcardTopicsMatching(topic string) []*Bus {
+	if topic == "" || strings.Contains(topic, "*") {
+		return nil
+	}
+	matches := make([]*Bus, 0)
+	for pattern, b := range w.topics {
+		if strings.Contains(pattern, "*") && topicMatches(pattern, topic) {
+			matches = append(matches, b)
+		}
+	}
+	slices.SortFunc(matches, func(a, b *Bus) int {
+		return strings.Compare(a.topic, b.topic)
+	})
+	return matches
+}
+
+// Does the topic pattern (which may contain wildcards) match the topic name?
+func topicMatches(pattern, topic string) bool {
+	patternSegments := strings.Split(pattern, ".")
+	topicSegments := strings.Split(topic, ".")
+	for i, seg := range patternSegments {
+		if seg == "*" && i == len(patternSegments)-1 {
+			return len(topicSegments) > i
+		}
+		if i >= len(topicSegments) {
+			return false
+		}
+		if seg != "*" && seg != topicSegments[i] {
+			return false
+		}
+	}
+	return len(patternSegments) == len(topicSegments)
+}
@@ -0,0 +1,75 @@
+package ent
+
+import (
+	"slices"
+	"testing"
+)
+
+type testListener struct {
+	CoreEntity
+	received []any
+}
+
+func (l *testListener) HandleMessage(_ *World, msg any) {
+	l.received = append(l.received, msg)
+}
+
+func TestTopicMatches(t *testing.T) {
+	tests := []struct {
+		pattern, topic string
+		want           bool
+	}{
+		{"asteroid.destroyed", "asteroid.destroyed", true},
+		{"asteroid.destroyed", "asteroid.mined", false},
+		{"asteroid.*", "asteroid.destroyed", true},
+		{"asteroid.*", "asteroid.mined.fully", true},
+		{"asteroid.*", "asteroid", false},
+		{"asteroid.*", "enemy.destroyed", false},
+		{"*.destroyed", "asteroid.destroyed", true},
+		{"*.destroyed", "asteroid.mined", false},
+		{"*.destroyed", "asteroid.big.destroyed", false},
+		{"asteroid.*.destroyed", "asteroid.big.destroyed", true},
+		{"asteroid.*.destroyed", "asteroid.destroyed", false},
+		{"*", "asteroid", true},
+		{"*", "asteroid.destroyed", true},
+		{"asteroid", "asteroid.destroyed", false},
+		{"asteroid.destroyed", "asteroid", false},
+	}
+	for _, tt := range tests {
+		if got := topicMatches(tt.pattern, tt.topic); got != tt.want {
+			t.Errorf("topicMatches(%q, %q) = %v, want %v", tt.pattern, tt.topic, got, tt.want)
+		}
+	}
+}
+
+func TestWildcardTopicSubscriptions(t *testing.T) {
+	w := NewWorld()
+	exact, wildcard, both, other := &testListener{}, &testListener{}, &testListener{}, &testListener{}
+	w.AddNow(exact, wildcard, both, other)
+	Subscribe(w.Topic("asteroid.destroyed"), exact, both)
+	Subscribe(w.Topic("asteroid.*"), wildcard, both)
+	Subscribe(w.Topic("enemy.*"), other)
+
+	Emit(w, w.Topic("asteroid.destroyed"), 1)
+	Emit(w, w.Topic("asteroid.mined"), 2)
+	// Emitting on a wildcard topic only reaches its own subscribers
+	Emit(w, w.Topic("asteroid.*"), 3)
+	Unsubscribe(w.Topic("asteroid.*"), both)
+	Emit(w, w.Topic("asteroid.destroyed"), 4)
+	Emit(w, w.Topic("asteroid.mined"), 5)
+
+	for _, tt := range []struct {
+		name     string
+		listener *testListener
+		want     []any
+	}{
+		{"exact", exact, []any{1, 4}},
+		{"wildcard", wildcard, []any{1, 2, 3, 4, 5}},
+		{"subscribed to both", both, []any{1, 2, 3, 4}},
+		{"other wildcard", other, []any{}},
+	} {
+		if !slices.Equal(append([]any{}, tt.listener.received...), tt.want) {
+			t.Errorf("%s received %v, want %v", tt.name, tt.listener.received, tt.want)
+		}
+	}
+}
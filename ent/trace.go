@@ -0,0 +1,154 @@
+package ent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// The way in which a traced message was emitted.
+type TraceKind string
+
+const (
+	TraceEmit         TraceKind = "emit"
+	TraceEmitDirectly TraceKind = "emit_directly"
+	TraceEmitToTag    TraceKind = "emit_to_tag"
+	TraceAsk          TraceKind = "ask"
+	TraceAskAll       TraceKind = "ask_all"
+)
+
+// A record of a single message being emitted, and what happened to it.
+type MessageTrace struct {
+	// The world frame the message was emitted or delivered in.
+	Frame uint64    `json:"frame"`
+	Kind  TraceKind `json:"kind"`
+	// The entity that was updating or handling a message when this message was emitted, if any.
+	Source EntityUUID `json:"source,omitempty"`
+	// The topic or bus name for Emit and AskAll, or the tag for EmitToTag.
+	Bus string `json:"bus,omitempty"`
+	// The type of the message, or of the query for asks.
+	PayloadType string `json:"payload_type"`
+	// The message was queued to be delivered later. Recipients are the entities it will be delivered to.
+	Queued bool `json:"queued,omitempty"`
+	// The message was queued earlier and is now being delivered.
+	Deferred bool `json:"deferred,omitempty"`
+	// Entities that received the message.
+	Recipients []EntityUUID `json:"recipients,omitempty"`
+	// Entities that will receive the message once they have been added to the world.
+	Waiting []EntityUUID `json:"waiting,omitempty"`
+	// Entities that were no longer in the world, so did not receive the message.
+	Dropped []EntityUUID `json:"dropped,omitempty"`
+}
+
+// An object that records messages as they are emitted in a world.
+type Tracer interface {
+	Trace(MessageTrace)
+}
+
+// Set the tracer that will record every message emitted in this world.
+// Pass nil to stop tracing.
+func (w *World) SetTracer(t Tracer) {
+	w.tracer = t
+}
+
+// Get the tracer of this world, or nil if it is not being traced.
+func (w *World) Tracer() Tracer {
+	return w.tracer
+}
+
+func (w *World) newTrace(kind TraceKind, bus string) MessageTrace {
+	return MessageTrace{
+		Frame:  w.frame,
+		Kind:   kind,
+		Source: w.activeEntity,
+		Bus:    bus,
+	}
+}
+
+func busName(b *Bus) string {
+	if b.topic != "" {
+		return b.topic
+	}
+	return fmt.Sprintf("bus@%p", b)
+}
+
+func payloadType(d any) string {
+	if q, ok := d.(queuedQuery); ok {
+		d = q.payload()
+	}
+	return fmt.Sprintf("%T", d)
+}
+
+// Create a tracer that keeps the most recent traces in memory.
+func NewRingTracer(size int) *RingTracer {
+	return &RingTracer{
+		traces: make([]MessageTrace, 0, max(size, 1)),
+	}
+}
+
+// A tracer that keeps a fixed number of the most recent traces.
+type RingTracer struct {
+	traces []MessageTrace
+	next   int
+}
+
+func (r *RingTracer) Trace(t MessageTrace) {
+	if len(r.traces) < cap(r.traces) {
+		r.traces = append(r.traces, t)
+		return
+	}
+	r.traces[r.next] = t
+	r.next = (r.next + 1) % len(r.traces)
+}
+
+// Get the recorded traces, oldest first.
+func (r *RingTracer) Traces() []MessageTrace {
+	traces := make([]MessageTrace, 0, len(r.traces))
+	traces = append(traces, r.traces[r.next:]...)
+	return append(traces, r.traces[:r.next]...)
+}
+
+// Forget all recorded traces.
+func (r *RingTracer) Clear() {
+	r.traces = r.traces[:0]
+	r.next = 0
+}
+
+// Create a tracer that writes each trace as a line of JSON.
+func NewJSONLTracer(w io.Writer) *JSONLTracer {
+	return &JSONLTracer{
+		enc: json.NewEncoder(w),
+	}
+}
+
+// A tracer that writes each trace as a line of JSON.
+type JSONLTracer struct {
+	enc *json.Encoder
+	err error
+}
+
+func (j *JSONLTracer) Trace(t MessageTrace) {
+	if j.err != nil {
+		return
+	}
+	j.err = j.enc.Encode(t)
+}
+
+// Get the first error that was encountered while writing traces.
+// Once an error has occurred, no more traces will be written.
+func (j *JSONLTracer) Err() error {
+	return j.err
+}
+
+// Create a tracer that passes every trace on to all of the given tracers.
+func MultiTracer(tracers ...Tracer) Tracer {
+	return multiTracer(tracers)
+}
+
+type multiTracer []Tracer
+
+func (m multiTracer) Trace(t MessageTrace) {
+	for _, tracer := range m {
+		tracer.Trace(t)
+	}
+}
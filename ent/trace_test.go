@@ -0,0 +1,136 @@
+package ent
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTraceEmits(t *testing.T) {
+	w := NewWorld()
+	tracer := NewRingTracer(16)
+	w.SetTracer(tracer)
+	listener, gone, waiting := &testListener{}, &testListener{}, &testListener{}
+	w.AddNow(listener, gone)
+	w.RemoveNow(gone)
+	w.Add(waiting)
+	bus := w.Topic("rocks")
+	Subscribe(bus, listener)
+
+	Emit(w, bus, 1)
+	EmitDirectly(w, "hi", listener, gone, waiting)
+	w.AddTags(listener, "tagged")
+	EmitToTag(w, "tagged", 2.5)
+
+	want := []MessageTrace{
+		{Kind: TraceEmit, Bus: "rocks", PayloadType: "int", Recipients: []EntityUUID{listener.UUID()}},
+		{Kind: TraceEmitDirectly, PayloadType: "string", Recipients: []EntityUUID{listener.UUID()}, Waiting: []EntityUUID{waiting.UUID()}, Dropped: []EntityUUID{gone.UUID()}},
+		{Kind: TraceEmitToTag, Bus: "tagged", PayloadType: "float64", Recipients: []EntityUUID{listener.UUID()}},
+	}
+	checkTraces(t, tracer.Traces(), want)
+}
+
+func TestTraceQueuedMessages(t *testing.T) {
+	w := NewWorld()
+	tracer := NewRingTracer(16)
+	w.SetTracer(tracer)
+	source, listener := &testEntity{}, &testListener{}
+	w.AddNow(source, listener)
+	bus := w.Topic("rocks")
+	Subscribe(bus, listener)
+	// Timers run with their owner as the source of what they emit
+	w.AfterFor(source, 0, func() { EmitLater(w, bus, 1) })
+	w.Update(nil, 1)
+
+	recipients := []EntityUUID{listener.UUID()}
+	want := []MessageTrace{
+		{Frame: 1, Kind: TraceEmit, Source: source.UUID(), Bus: "rocks", PayloadType: "int", Queued: true, Recipients: recipients},
+		{Frame: 1, Kind: TraceEmit, Source: source.UUID(), Bus: "rocks", PayloadType: "int", Deferred: true, Recipients: recipients},
+	}
+	checkTraces(t, tracer.Traces(), want)
+}
+
+func TestTraceAsks(t *testing.T) {
+	w := NewWorld()
+	tracer := NewRingTracer(16)
+	w.SetTracer(tracer)
+	responder, gone := &testResponder{value: 1}, &testResponder{}
+	w.AddNow(responder, gone)
+	bus := w.Topic("questions")
+	Subscribe(bus, responder, gone)
+	w.RemoveNow(gone)
+
+	Ask[int](w, responder, "value")
+	Ask[int](w, gone, "value")
+	AskAll[int](w, bus, "value")
+	AskLater(w, responder, "value", func(int, bool) {})
+	w.Update(nil, 1)
+
+	recipients := []EntityUUID{responder.UUID()}
+	want := []MessageTrace{
+		{Kind: TraceAsk, PayloadType: "string", Recipients: recipients},
+		{Kind: TraceAsk, PayloadType: "string", Dropped: []EntityUUID{gone.UUID()}},
+		{Kind: TraceAskAll, Bus: "questions", PayloadType: "string", Recipients: recipients, Dropped: []EntityUUID{gone.UUID()}},
+		{Kind: TraceAsk, PayloadType: "string", Queued: true, Recipients: recipients},
+		{Frame: 1, Kind: TraceAsk, PayloadType: "string", Deferred: true, Recipients: recipients},
+	}
+	checkTraces(t, tracer.Traces(), want)
+}
+
+func TestRingTracerKeepsNewest(t *testing.T) {
+	r := NewRingTracer(2)
+	for i := range 5 {
+		r.Trace(MessageTrace{Frame: uint64(i)})
+	}
+	frames := make([]uint64, 0)
+	for _, trace := range r.Traces() {
+		frames = append(frames, trace.Frame)
+	}
+	if !slices.Equal(frames, []uint64{3, 4}) {
+		t.Fatalf("got frames %v, want the newest two oldest first", frames)
+	}
+	r.Clear()
+	if len(r.Traces()) != 0 {
+		t.Fatal("traces should be cleared")
+	}
+}
+
+func TestJSONLTracer(t *testing.T) {
+	var out bytes.Buffer
+	ring := NewRingTracer(4)
+	w := NewWorld()
+	w.SetTracer(MultiTracer(NewJSONLTracer(&out), ring))
+	listener := &testListener{}
+	w.AddNow(listener)
+	EmitDirectly(w, 1, listener)
+	EmitDirectly(w, "two", listener)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || len(ring.Traces()) != 2 {
+		t.Fatalf("every trace should go to both tracers, got %d lines and %d traces", len(lines), len(ring.Traces()))
+	}
+	var trace MessageTrace
+	if err := json.Unmarshal([]byte(lines[1]), &trace); err != nil {
+		t.Fatal(err)
+	}
+	if trace.Kind != TraceEmitDirectly || trace.PayloadType != "string" || !slices.Equal(trace.Recipients, []EntityUUID{listener.UUID()}) {
+		t.Fatalf("unexpected trace %+v", trace)
+	}
+}
+
+func checkTraces(t *testing.T, got, want []MessageTrace) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d traces, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Frame != w.Frame || g.Kind != w.Kind || g.Source != w.Source || g.Bus != w.Bus || g.PayloadType != w.PayloadType ||
+			g.Queued != w.Queued || g.Deferred != w.Deferred ||
+			!slices.Equal(g.Recipients, w.Recipients) || !slices.Equal(g.Waiting, w.Waiting) || !slices.Equal(g.Dropped, w.Dropped) {
+			t.Errorf("trace %d: got %+v, want %+v", i, g, w)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package ent
+
+import (
+	"math"
+
+	"github.com/gopxl/pixel"
+)
+
+// An animation of some properties over time, that can be moved to any point in time.
+type Tween interface {
+	// Get the length of the tween in seconds. May be infinite.
+	Duration() float64
+	// Set the tweened properties to how they should be at the given time.
+	// Times outside of the duration should be treated as the start or end.
+	Seek(t float64)
+}
+
+// Create a tween that moves a value from one value to another, using lerp to interpolate between them.
+// A nil easing is linear.
+func TweenValue[T any](from, to T, seconds float64, ease Easing, lerp func(a, b T, t float64) T, set func(T)) Tween {
+	if ease == nil {
+		ease = EaseLinear
+	}
+	return &valueTween[T]{from, to, seconds, ease, lerp, set}
+}
+
+// Create a tween that moves a float from one value to another.
+func TweenFloat(from, to, seconds float64, ease Easing, set func(float64)) Tween {
+	return TweenValue(from, to, seconds, ease, func(a, b, t float64) float64 {
+		return a + (b-a)*t
+	}, set)
+}
+
+// Create a tween that moves a vector from one value to another.
+func TweenVec(from, to pixel.Vec, seconds float64, ease Easing, set func(pixel.Vec)) Tween {
+	return TweenValue(from, to, seconds, ease, pixel.Lerp, set)
+}
+
+// Create a tween that rotates an angle (in radians) from one value to another, the shortest way round.
+func TweenAngle(from, to, seconds float64, ease Easing, set func(float64)) Tween {
+	return TweenValue(from, to, seconds, ease, func(a, b, t float64) float64 {
+		return a + math.Remainder(b-a, 2*math.Pi)*t
+	}, set)
+}
+
+// Create a tween that blends a color from one value to another.
+func TweenColor(from, to pixel.RGBA, seconds float64, ease Easing, set func(pixel.RGBA)) Tween {
+	return TweenValue(from, to, seconds, ease, func(a, b pixel.RGBA, t float64) pixel.RGBA {
+		return a.Scaled(1 - t).Add(b.Scaled(t))
+	}, set)
+}
+
+type valueTween[T any] struct {
+	from     T
+	to       T
+	duration float64
+	ease     Easing
+	lerp     func(T, T, float64) T
+	set      func(T)
+}
+
+func (v *valueTween[T]) Duration() float64 {
+	return v.duration
+}
+
+func (v *valueTween[T]) Seek(t float64) {
+	progress := 1.0
+	if v.duration > 0 {
+		progress = math.Max(0, math.Min(1, t/v.duration))
+	}
+	v.set(v.lerp(v.from, v.to, v.ease(progress)))
+}
+
+// Create a tween that does nothing for a while. Useful in sequences.
+func Delay(seconds float64) Tween {
+	return delayTween(seconds)
+}
+
+type delayTween float64
+
+func (d delayTween) Duration() float64 { return float64(d) }
+func (d delayTween) Seek(float64)      {}
+
+// Create a tween that calls the function when it is passed going forwards. Useful in sequences.
+func Call(fn func()) Tween {
+	return &callTween{fn: fn}
+}
+
+type callTween struct {
+	fn     func()
+	called bool
+}
+
+func (c *callTween) Duration() float64 { return 0 }
+
+func (c *callTween) Seek(t float64) {
+	if t < 0 {
+		c.called = false
+		return
+	}
+	if !c.called {
+		c.called = true
+		c.fn()
+	}
+}
+
+// Create a tween that plays each tween one after the other.
+func Sequence(tweens ...Tween) Tween {
+	return &sequenceTween{tweens: tweens}
+}
+
+type sequenceTween struct {
+	tweens []Tween
+	last   float64
+}
+
+func (s *sequenceTween) Duration() float64 {
+	total := 0.0
+	for _, t := range s.tweens {
+		total += t.Duration()
+	}
+	return total
+}
+
+// Seek every tween that overlaps the time moved through since the last seek.
+// When going backwards, tweens are seeked in reverse so the earliest one ends up in control.
+func (s *sequenceTween) Seek(t float64) {
+	lo, hi := min(s.last, t), max(s.last, t)
+	starts := make([]float64, len(s.tweens))
+	start := 0.0
+	for i, tween := range s.tweens {
+		starts[i] = start
+		start += tween.Duration()
+	}
+	for n := range s.tweens {
+		i := n
+		if t < s.last {
+			i = len(s.tweens) - 1 - n
+		}
+		tween := s.tweens[i]
+		if starts[i] <= hi && starts[i]+tween.Duration() >= lo {
+			tween.Seek(t - starts[i])
+		}
+	}
+	s.last = t
+}
+
+// Create a tween that plays all tweens at the same time.
+func Parallel(tweens ...Tween) Tween {
+	return parallelTween(tweens)
+}
+
+type parallelTween []Tween
+
+func (p parallelTween) Duration() float64 {
+	longest := 0.0
+	for _, t := range p {
+		longest = max(longest, t.Duration())
+	}
+	return longest
+}
+
+func (p parallelTween) Seek(t float64) {
+	for _, tween := range p {
+		tween.Seek(t)
+	}
+}
+
+// Create a tween that plays the tween forwards then backwards.
+func Yoyo(t Tween) Tween {
+	return yoyoTween{t}
+}
+
+type yoyoTween struct {
+	tween Tween
+}
+
+func (y yoyoTween) Duration() float64 {
+	return y.tween.Duration() * 2
+}
+
+func (y yoyoTween) Seek(t float64) {
+	d := y.tween.Duration()
+	if t <= d {
+		y.tween.Seek(t)
+	} else {
+		y.tween.Seek(2*d - t)
+	}
+}
+
+// Create a tween that plays the tween the given number of times, or forever if times is not positive.
+func Loop(t Tween, times int) Tween {
+	return &loopTween{tween: t, times: times}
+}
+
+type loopTween struct {
+	tween     Tween
+	times     int
+	iteration int
+}
+
+func (l *loopTween) Duration() float64 {
+	if l.times <= 0 {
+		return math.Inf(1)
+	}
+	return l.tween.Duration() * float64(l.times)
+}
+
+// When moving into a new iteration, the previous one is finished then the tween is rewound to the start.
+func (l *loopTween) Seek(t float64) {
+	d := l.tween.Duration()
+	if d <= 0 || t >= l.Duration() {
+		l.tween.Seek(t)
+		return
+	}
+	iteration := int(math.Floor(math.Max(t, 0) / d))
+	if iteration != l.iteration {
+		l.tween.Seek(d)
+		l.tween.Seek(0)
+		l.iteration = iteration
+	}
+	l.tween.Seek(t - float64(iteration)*d)
+}
+
+// Create a player that plays the tween forwards in time, starting at time zero.
+func NewTweenPlayer(t Tween) *TweenPlayer {
+	t.Seek(0)
+	return &TweenPlayer{tween: t}
+}
+
+// Plays a tween forwards in time.
+type TweenPlayer struct {
+	tween      Tween
+	elapsed    float64
+	onComplete []func()
+	done       bool
+}
+
+// Move the tween forwards in time, returning true once it has completed (or been cancelled).
+// Completion callbacks are called on the update that the tween completes.
+func (p *TweenPlayer) Update(dt float64) bool {
+	if p.done {
+		return true
+	}
+	p.elapsed += dt
+	p.tween.Seek(p.elapsed)
+	if p.elapsed < p.tween.Duration() {
+		return false
+	}
+	p.done = true
+	for _, fn := range p.onComplete {
+		fn()
+	}
+	return true
+}
+
+// Call the function when the tween completes.
+func (p *TweenPlayer) OnComplete(fn func()) *TweenPlayer {
+	p.onComplete = append(p.onComplete, fn)
+	return p
+}
+
+// Stop the tween where it is, without calling completion callbacks.
+func (p *TweenPlayer) Cancel() {
+	p.done = true
+}
+
+// Is the tween still playing?
+func (p *TweenPlayer) Active() bool {
+	return !p.done
+}
+
+// Get the amount of time the tween has been playing for.
+func (p *TweenPlayer) Elapsed() float64 {
+	return p.elapsed
+}
+
+// Play the tween on world time, starting now.
+func (w *World) Tween(t Tween) *TweenPlayer {
+	return w.playTween(t, 0)
+}
+
+// Like Tween, but the tween is cancelled when the entity is removed from the world.
+func (w *World) TweenFor(e EntityUUIDer, t Tween) *TweenPlayer {
+	return w.playTween(t, handleOf(e, "play a tween for"))
+}
+
+func (w *World) playTween(t Tween, owner EntityUUID) *TweenPlayer {
+	p := NewTweenPlayer(t)
+	if w.buffering() {
+		w.commands.record(func(w *World) { w.startTween(p, owner) })
+	} else {
+		w.startTween(p, owner)
+	}
+	return p
+}
+
+func (w *World) startTween(p *TweenPlayer, owner EntityUUID) {
+	w.tweens = append(w.tweens, ownedTween{p, owner})
+	if owner != 0 {
+		w.bindToEntity(owner, p)
+	}
+}
+
+type ownedTween struct {
+	player *TweenPlayer
+	owner  EntityUUID
+}
+
+// Move all playing tweens forwards, forgetting any that have completed.
+// Tweens started while this is happening will start moving next update.
+func (w *World) updateTweens(dt float64) {
+	playing := w.tweens
+	w.tweens = nil
+	stillPlaying := make([]ownedTween, 0, len(playing))
+	for _, t := range playing {
+		w.activeEntity = t.owner
+		if !t.player.Update(dt) {
+			stillPlaying = append(stillPlaying, t)
+		}
+	}
+	w.activeEntity = 0
+	w.tweens = append(stillPlaying, w.tweens...)
+}
@@ -0,0 +1,129 @@
+package ent
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTweens(t *testing.T) {
+	const dt = 0.5
+	tests := []struct {
+		name  string
+		tween func(set func(float64)) Tween
+		// The value after each update.
+		want []float64
+		// The update the tween completes on, counting from 1, or 0 if it never completes.
+		completesOn int
+	}{
+		{
+			"float",
+			func(set func(float64)) Tween { return TweenFloat(0, 4, 2, nil, set) },
+			[]float64{1, 2, 3, 4, 4},
+			4,
+		},
+		{
+			"eased",
+			func(set func(float64)) Tween { return TweenFloat(0, 1, 1, EaseInQuad, set) },
+			[]float64{0.25, 1, 1},
+			2,
+		},
+		{
+			"sequence with delay",
+			func(set func(float64)) Tween {
+				return Sequence(TweenFloat(0, 2, 1, nil, set), Delay(0.5), TweenFloat(2, 0, 1, nil, set))
+			},
+			[]float64{1, 2, 2, 1, 0, 0},
+			5,
+		},
+		{
+			"parallel lasts as long as the longest",
+			func(set func(float64)) Tween {
+				return Parallel(Delay(2), TweenFloat(0, 1, 1, nil, set))
+			},
+			[]float64{0.5, 1, 1, 1, 1},
+			4,
+		},
+		{
+			"yoyo",
+			func(set func(float64)) Tween { return Yoyo(TweenFloat(0, 2, 1, nil, set)) },
+			[]float64{1, 2, 1, 0, 0},
+			4,
+		},
+		{
+			"loop twice",
+			func(set func(float64)) Tween { return Loop(TweenFloat(0, 2, 1, nil, set), 2) },
+			[]float64{1, 0, 1, 2, 2},
+			4,
+		},
+		{
+			"loop forever",
+			func(set func(float64)) Tween { return Loop(TweenFloat(0, 2, 1, nil, set), 0) },
+			[]float64{1, 0, 1, 0, 1, 0},
+			0,
+		},
+		{
+			"looped yoyo",
+			func(set func(float64)) Tween { return Loop(Yoyo(TweenFloat(0, 2, 1, nil, set)), 0) },
+			[]float64{1, 2, 1, 0, 1, 2},
+			0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWorld()
+			value := -1.0
+			completions := make([]int, 0)
+			step := 0
+			player := w.Tween(tt.tween(func(v float64) { value = v }))
+			player.OnComplete(func() { completions = append(completions, step) })
+			if value != 0 {
+				t.Fatalf("tween should start at its start, got %v", value)
+			}
+			got := make([]float64, 0, len(tt.want))
+			for step = 1; step <= len(tt.want); step++ {
+				w.Update(nil, dt)
+				got = append(got, value)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got values %v, want %v", got, tt.want)
+			}
+			wantCompletions := []int{}
+			if tt.completesOn > 0 {
+				wantCompletions = []int{tt.completesOn}
+			}
+			if !slices.Equal(completions, wantCompletions) {
+				t.Errorf("completed on updates %v, want %v", completions, wantCompletions)
+			}
+			if player.Active() != (tt.completesOn == 0) {
+				t.Errorf("player should be active only if it never completes, active is %v", player.Active())
+			}
+		})
+	}
+}
+
+func TestTweenCallsInSequence(t *testing.T) {
+	w := NewWorld()
+	calls := make([]float64, 0)
+	w.Tween(Loop(Sequence(Delay(1), Call(func() { calls = append(calls, w.Time()) })), 3))
+	for range 8 {
+		w.Update(nil, 0.5)
+	}
+	if !slices.Equal(calls, []float64{1, 2, 3}) {
+		t.Fatalf("call should run once each loop, ran at %v", calls)
+	}
+}
+
+func TestTweenCancelledWithOwner(t *testing.T) {
+	w := NewWorld()
+	e := &testEntity{}
+	w.AddNow(e)
+	value, completed := 0.0, false
+	player := w.TweenFor(e, TweenFloat(0, 2, 2, nil, func(v float64) { value = v }))
+	player.OnComplete(func() { completed = true })
+	w.Update(nil, 0.5)
+	w.RemoveNow(e)
+	w.Update(nil, 5)
+	if value != 0.5 || completed || player.Active() {
+		t.Fatalf("tween should stop where it was without completing, got %v, completed %v", value, completed)
+	}
+}
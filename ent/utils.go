@@ -10,19 +10,12 @@ import (
 // Filter the iterator of entities to only those of the given type.
 func OfType[T any](xs iter.Seq[Entity]) iter.Seq[T] {
 	return func(yield func(T) bool) {
-		next, stop := iter.Pull(xs)
-		for {
-			e, ok := next()
-			if !ok {
-				stop()
-				return
-			}
+		for e := range xs {
 			t, ok := e.(T)
 			if !ok {
 				continue
 			}
 			if !yield(t) {
-				stop()
 				return
 			}
 		}
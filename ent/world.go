@@ -2,6 +2,7 @@ package ent
 
 import (
 	"iter"
+	"reflect"
 	"slices"
 
 	"github.com/gopxl/pixel"
@@ -10,101 +11,210 @@ import (
 
 // A collection of entities that can be indexed and updated in various ways.
 type World struct {
-	byIDLookup              map[EntityUUID]Entity
+	*worldState
+	// Set when this is the view of the world given to one entity during a parallel update.
+	commands     *commandBuffer
+	activeEntity EntityUUID
+}
+
+// The state of a world, which is shared with the views used for parallel updates.
+type worldState struct {
+	slots                   []entitySlot
+	freeSlots               []uint32
+	slotsFreed              uint64
 	allEntities             *Index[Entity]
 	orderedByDraw           *Index[Drawer]
 	orderedByUpdate         *Index[Updater]
 	physicsBodies           *Index[PhysicsBody]
+	preUpdaters             *Index[PreUpdater]
+	prePhysicsUpdaters      *Index[PrePhysicsUpdater]
+	postPhysicsUpdaters     *Index[PostPhysicsUpdater]
+	lateUpdaters            *Index[LateUpdater]
+	timeZones               *Index[TimeZone]
+	systems                 [phaseCount][]system
+	systemSeq               uint64
+	updateWorkers           int
+	collisionWorkers        int
+	profiler                *Profiler
+	lastCollisions          []Collision
+	timeScale               float64
+	pausedTimeScale         float64
+	pendingSteps            int
+	unscaledDt              float64
+	indexes                 []worldIndex
+	indexesByType           map[reflect.Type]registeredIndex
 	byTags                  map[string]*Index[Entity]
+	parents                 map[EntityUUID]EntityUUID
+	children                map[EntityUUID][]EntityUUID
+	topics                  map[string]*Bus
 	queuedAdd               []Entity
 	queuedAddWaitingSignals map[EntityUUID][]any
 	queuedRemove            []Entity
+	queuedRefresh           []Entity
+	queuedMessages          []queuedMessage
+	addedObservers          observerList[func(Entity)]
+	removedObservers        observerList[func(Entity)]
+	tagObservers            observerList[func(Entity, string, bool)]
+	maxMessageDepth         int
+	tracer                  Tracer
+	frame                   uint64
+	time                    float64
+	timers                  timerHeap
+	timerSeq                uint64
+	boundTasks              map[EntityUUID][]boundTask
+	tweens                  []ownedTween
+	scripts                 []*ScriptHandle
+	queryCaches             map[queryCacheKey]*queryCache
 }
 
 // Create a new, empty, world.
 func NewWorld() *World {
-	return &World{
-		byIDLookup:              make(map[EntityUUID]Entity),
-		allEntities:             NewUnorderedIndex[Entity](),
-		orderedByDraw:           NewOrderedIndex(func(d Drawer) int { return d.DrawLayer() }),
-		orderedByUpdate:         NewOrderedIndex(func(u Updater) int { return u.UpdateLayer() }),
-		physicsBodies:           NewUnorderedIndex[PhysicsBody](),
+	w := &World{worldState: &worldState{
+		indexesByType:           make(map[reflect.Type]registeredIndex),
 		byTags:                  make(map[string]*Index[Entity], 0),
+		parents:                 make(map[EntityUUID]EntityUUID),
+		children:                make(map[EntityUUID][]EntityUUID),
+		topics:                  make(map[string]*Bus),
 		queuedAddWaitingSignals: make(map[EntityUUID][]any),
-	}
+		maxMessageDepth:         DefaultMaxMessageDepth,
+		boundTasks:              make(map[EntityUUID][]boundTask),
+		queryCaches:             make(map[queryCacheKey]*queryCache),
+		timeScale:               1,
+	}}
+	w.allEntities = RegisterIndex[Entity](w, nil)
+	w.orderedByDraw = RegisterIndex(w, Drawer.DrawLayer)
+	w.orderedByUpdate = RegisterIndex(w, Updater.UpdateLayer)
+	w.physicsBodies = RegisterIndex[PhysicsBody](w, nil)
+	w.preUpdaters = RegisterIndex[PreUpdater](w, nil)
+	w.prePhysicsUpdaters = RegisterIndex[PrePhysicsUpdater](w, nil)
+	w.postPhysicsUpdaters = RegisterIndex[PostPhysicsUpdater](w, nil)
+	w.lateUpdaters = RegisterIndex[LateUpdater](w, nil)
+	w.timeZones = RegisterIndex[TimeZone](w, nil)
+	return w
 }
 
 // AddNow the entities to the world, adding it to all relevant indexes.
 // The entity tags at this point in time will now be used of the entity.
-// Each entity can only be added to the world once.
-// Will also call AfterAdd, and will then send any queued signals.
+// Each entity can only be added to the world once, and is given a UUID by the world if it does not already have one here.
+// Will also call AfterAdd, will then send any queued signals, and finally notify OnEntityAdded observers.
+// Children whose parent has already been removed will not be added.
 func (es *World) AddNow(toAdd ...Entity) {
+	if es.buffering() {
+		es.commands.record(func(w *World) { w.AddNow(toAdd...) })
+		return
+	}
 	for _, e := range toAdd {
-		uid := e.UUID()
-		if _, ok := es.byIDLookup[uid]; ok {
+		if es.Has(e) {
 			continue
 		}
-		es.byIDLookup[uid] = e
-		es.allEntities.Add(e)
-		es.orderedByDraw.AddUntyped(e)
-		es.orderedByUpdate.AddUntyped(e)
-		es.physicsBodies.AddUntyped(e)
+		uid := es.claimSlot(e, slotLive).entity.UUID()
+		if parentID, ok := es.parents[uid]; ok && !es.HasOrQueued(parentID) {
+			delete(es.parents, uid)
+			delete(es.queuedAddWaitingSignals, uid)
+			es.freeSlot(uid)
+			continue
+		}
+		for _, index := range es.indexes {
+			index.AddUntyped(e)
+		}
+		prev := es.activeEntity
+		es.activeEntity = uid
 		e.AfterAdd(es)
+		es.activeEntity = prev
 		for _, sig := range es.queuedAddWaitingSignals[e.UUID()] {
-			e.HandleMessage(es, sig)
+			es.handleMessageAs(e, sig)
 		}
 		delete(es.queuedAddWaitingSignals, e.UUID())
+		es.addedObservers.notify(func(fn func(Entity)) { fn(e) })
 	}
 }
 
 // Queue the entities to be added to the world when appropriate.
+// Each entity is given a UUID straight away, so it can be referred to before it is added.
 func (w *World) Add(toInstantiate ...Entity) {
+	if w.buffering() {
+		w.commands.record(func(w *World) { w.Add(toInstantiate...) })
+		return
+	}
+	for _, e := range toInstantiate {
+		w.claimSlot(e, slotQueued)
+	}
 	w.queuedAdd = append(w.queuedAdd, toInstantiate...)
 }
 
-// RemoveNow the entity from the world.
+// RemoveNow the entity from the world, along with all of its children.
+// BeforeRemove is called on the entity first, then its children are removed, then OnEntityRemoved observers are notified.
+// If the entity is only queued to be added, it will no longer be added, and any signals waiting for it are dropped.
 // If the entity is not there, this will be a no-op.
 func (es *World) RemoveNow(toRemove ...Entity) {
+	if es.buffering() {
+		es.commands.record(func(w *World) { w.RemoveNow(toRemove...) })
+		return
+	}
 	for _, e := range toRemove {
-		ok := es.allEntities.Remove(e)
-		if !ok {
+		if !es.Has(e) {
+			es.cancelQueuedAdd(e)
 			continue
 		}
-		delete(es.byIDLookup, e.UUID())
-		es.orderedByDraw.RemoveUntyped(e)
-		es.orderedByUpdate.RemoveUntyped(e)
-		es.physicsBodies.RemoveUntyped(e)
+		if br, ok := e.(BeforeRemover); ok {
+			prev := es.activeEntity
+			es.activeEntity = e.UUID()
+			br.BeforeRemove(es)
+			es.activeEntity = prev
+		}
+		es.removeFromHierarchy(e)
+		es.cancelBoundTasks(e.UUID())
+		delete(es.queuedAddWaitingSignals, e.UUID())
+		for _, index := range es.indexes {
+			index.RemoveUntyped(e)
+		}
+		removedTags := make([]string, 0)
 		for tag, index := range es.byTags {
-			if index.Remove(e) && es.byTags[tag].Len() == 0 {
-				delete(es.byTags, tag)
+			if index.Remove(e) {
+				removedTags = append(removedTags, tag)
+				if index.Len() == 0 {
+					delete(es.byTags, tag)
+				}
 			}
 		}
+		slices.Sort(removedTags)
+		for _, tag := range removedTags {
+			es.notifyTagChanged(e, tag, false)
+		}
+		es.removedObservers.notify(func(fn func(Entity)) { fn(e) })
+		es.freeSlot(e.UUID())
 	}
 }
 
 // Queue the entities to be removed to the world when appropriate.
 func (w *World) Remove(toDestroy ...Entity) {
+	if w.buffering() {
+		w.commands.record(func(w *World) { w.Remove(toDestroy...) })
+		return
+	}
 	w.queuedRemove = append(w.queuedRemove, toDestroy...)
 }
 
+// Queue the entities to be moved to their new place in the draw and update order (and any other ordered indexes).
+// Call this after changing the value returned by DrawLayer or UpdateLayer.
+func (w *World) RefreshLayers(toRefresh ...Entity) {
+	if w.buffering() {
+		w.commands.record(func(w *World) { w.RefreshLayers(toRefresh...) })
+		return
+	}
+	w.queuedRefresh = append(w.queuedRefresh, toRefresh...)
+}
+
 // Does the world contain this entity / uuid already?
 func (es *World) Has(e EntityUUIDer) bool {
-	_, ok := es.byIDLookup[e.UUID()]
-	return ok
+	slot, ok := es.slotOf(e.UUID())
+	return ok && slot.state == slotLive
 }
 
 // Does the world contain this entity / uuid already, or is it queued to be added?
 func (es *World) HasOrQueued(id EntityUUIDer) bool {
-	_, ok := es.byIDLookup[id.UUID()]
-	if ok {
-		return true
-	}
-	for _, e := range es.queuedAdd {
-		if e.UUID() == id {
-			return true
-		}
-	}
-	return false
+	_, ok := es.slotOf(id.UUID())
+	return ok
 }
 
 // Get all the entities for the given tag.
@@ -118,83 +228,218 @@ func (es *World) WithTag(tag string) iter.Seq[Entity] {
 
 // Get the specific entity with the given UUID
 func (es *World) WithUUID(id EntityUUID) (Entity, bool) {
-	e, ok := es.byIDLookup[id]
-	return e, ok
+	slot, ok := es.slotOf(id)
+	if !ok || slot.state != slotLive {
+		return nil, false
+	}
+	return slot.entity, true
+}
+
+// Get the tags the entity has, in sorted order.
+func (es *World) TagsOf(e EntityUUIDer) []string {
+	tags := make([]string, 0)
+	for tag, index := range es.byTags {
+		if _, ok := index.containsUUIDs[e.UUID()]; ok {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+	return tags
 }
 
 // Add the tags to the specific object.
 func (es *World) AddTags(e Entity, tags ...string) {
+	if es.buffering() {
+		es.commands.record(func(w *World) { w.AddTags(e, tags...) })
+		return
+	}
 	for _, tag := range tags {
 		if _, ok := es.byTags[tag]; !ok {
 			es.byTags[tag] = NewUnorderedIndex[Entity]()
 		}
-		es.byTags[tag].Add(e)
+		if es.byTags[tag].Add(e) {
+			es.notifyTagChanged(e, tag, true)
+		}
 	}
 }
 
 // Remove the tags from the specific object.
 func (es *World) RemoveTags(e Entity, tags ...string) {
+	if es.buffering() {
+		es.commands.record(func(w *World) { w.RemoveTags(e, tags...) })
+		return
+	}
 	for _, tag := range tags {
 		index, ok := es.byTags[tag]
 		if !ok {
 			continue
 		}
-		if index.Remove(e) && es.byTags[tag].Len() == 0 {
+		if !index.Remove(e) {
+			continue
+		}
+		if index.Len() == 0 {
 			delete(es.byTags, tag)
 		}
+		es.notifyTagChanged(e, tag, false)
 	}
 }
 
-// Update the world at the provided time interval.
-// First, run all update steps.
-// Then, add and remove all new entities.
-// Finally, resolve physics then run collision handlers.
+// Update the world at the provided time interval, scaled by the time scale, running each phase in order.
+// In each phase, the entity hooks are called first, then any systems registered for that phase.
+// While paused, only Unscaled Updaters are updated, then queued entities and messages are applied.
+// Each entity hook and physics body is given its own dt, which is also scaled by any TimeZones it is inside.
+//
+// PhasePreUpdate: PreUpdaters.
+// PhaseUpdate: Updaters, with children updated straight after their parent.
+// Then, run any timers that are due, move tweens forwards and resume scripts.
+// Then, add and remove all new entities, and deliver any messages that were queued to be sent later.
+// PhasePrePhysics: PrePhysicsUpdaters.
+// Then, resolve physics and run collision handlers.
+// PhasePostPhysics: PostPhysicsUpdaters.
+// PhaseLateUpdate: LateUpdaters.
 func (es *World) Update(win *pixelgl.Window, dt float64) {
-	for e := range es.orderedByUpdate.All() {
-		e.Update(win, es, dt)
+	es.unscaledDt = dt
+	scale := es.timeScale
+	if scale == 0 && es.pendingSteps > 0 {
+		es.pendingSteps--
+		scale = 1
 	}
-	for _, e := range es.queuedAdd {
-		if !es.Has(e) {
-			es.AddNow(e)
-		}
+	if scale == 0 {
+		es.updatePaused(win, dt)
+		return
 	}
-	es.queuedAdd = nil
-	for _, e := range es.queuedRemove {
-		if es.Has(e) {
-			es.RemoveNow(e)
-		}
+	dt *= scale
+	es.frame++
+	es.time += dt
+	if es.profiler != nil {
+		es.profiler.beginFrame(es.frame)
+	}
+	runPhase(es, PhasePreUpdate, es.preUpdaters, win, dt, func(e PreUpdater, dt float64) { e.PreUpdate(win, es, dt) })
+
+	end := es.profileSection(SectionUpdate)
+	es.updateAll(win, dt)
+	es.runSystems(PhaseUpdate, win, dt)
+	end()
+	end = es.profileSection(SectionTasks)
+	es.runTimers()
+	es.updateTweens(dt)
+	es.updateScripts(dt)
+	end()
+	end = es.profileSection(SectionQueued)
+	es.applyQueued()
+	end()
+	end = es.profileSection(SectionMessages)
+	es.deliverQueuedMessages()
+	end()
+
+	runPhase(es, PhasePrePhysics, es.prePhysicsUpdaters, win, dt, func(e PrePhysicsUpdater, dt float64) { e.PrePhysicsUpdate(win, es, dt) })
+	es.updatePhysics(dt)
+	runPhase(es, PhasePostPhysics, es.postPhysicsUpdaters, win, dt, func(e PostPhysicsUpdater, dt float64) { e.PostPhysicsUpdate(win, es, dt) })
+	runPhase(es, PhaseLateUpdate, es.lateUpdaters, win, dt, func(e LateUpdater, dt float64) { e.LateUpdate(win, es, dt) })
+	if es.profiler != nil && es.profiler.current != nil {
+		es.profiler.current.numEntities = es.allEntities.Len()
 	}
-	es.queuedRemove = nil
+}
 
+// Move all active physics bodies, then resolve collisions and run collision handlers.
+func (es *World) updatePhysics(dt float64) {
+	end := es.profileSection(SectionIntegrate)
 	fizBodies := slices.Collect(es.physicsBodies.All())
 	for _, body := range fizBodies {
 		body, ok := body.(ActivePhysicsBody)
 		if ok && body.IsPhysicsActive() {
-			body.PysicsUpdate(dt)
+			body.PysicsUpdate(es.dtFor(body, dt))
 		}
 	}
-	cols := StatelessCollisionPhysics(fizBodies)
+	end()
 
+	end = es.profileSection(SectionCollide)
+	var cols []Collision
+	if es.collisionWorkers > 1 {
+		cols = ParallelCollisionPhysics(fizBodies, es.collisionWorkers)
+	} else {
+		cols = StatelessCollisionPhysics(fizBodies)
+	}
+	end()
+	es.lastCollisions = cols
+	if es.profiler != nil && es.profiler.current != nil {
+		es.profiler.current.collisions += len(cols)
+	}
+
+	end = es.profileSection(SectionCollisionListeners)
+	defer end()
 	for _, col := range cols {
 		self, ok := col.Self.(CollisionListener)
 		if ok {
+			es.activeEntity = col.Self.UUID()
 			self.OnCollision(col)
 		}
 		col = col.ForOther()
 		self, ok = col.Self.(CollisionListener)
 		if ok {
+			es.activeEntity = col.Self.UUID()
 			self.OnCollision(col)
 		}
 	}
+	es.activeEntity = 0
+}
+
+// Add then remove all queued entities, then move any entities whose layers have changed.
+// Entities that are queued to be both added and removed are never added.
+// Anything queued while this is happening will be applied next time.
+func (es *World) applyQueued() {
+	toAdd, toRemove, toRefresh := es.queuedAdd, es.queuedRemove, es.queuedRefresh
+	es.queuedAdd, es.queuedRemove, es.queuedRefresh = nil, nil, nil
+	removing := make(map[EntityUUID]struct{}, len(toRemove))
+	for _, e := range toRemove {
+		removing[e.UUID()] = struct{}{}
+	}
+	for _, e := range toAdd {
+		if _, ok := removing[e.UUID()]; ok {
+			if !es.Has(e) {
+				es.cancelQueuedAdd(e)
+			}
+			continue
+		}
+		if !es.Has(e) {
+			es.AddNow(e)
+		}
+	}
+	for _, e := range toRemove {
+		if es.Has(e) {
+			es.RemoveNow(e)
+		}
+	}
+	for _, e := range toRefresh {
+		if !es.Has(e) {
+			continue
+		}
+		for _, index := range es.indexes {
+			index.RefreshUntyped(e)
+		}
+	}
 }
 
-// Call predraw on all entities, then call draw.
+// Get the number of times Update has been called on this world.
+func (es *World) Frame() uint64 {
+	return es.frame
+}
+
+// Call predraw on all entities, then run PhasePreDraw systems, then call draw.
 // Pass the provided world to screen mapping to all draw calls.
 func (es *World) Draw(win *pixelgl.Window, worldToScreen pixel.Matrix) {
+	end := es.profileSection(SectionPreDraw)
 	for e := range es.orderedByDraw.All() {
-		e.PreDraw(win)
+		es.profileEntity(SectionPreDraw, e, e.DrawLayer(), func() { e.PreDraw(win) })
 	}
+	es.runSystems(PhasePreDraw, win, 0)
+	end()
+	end = es.profileSection(SectionDraw)
+	defer end()
 	for e := range es.orderedByDraw.All() {
-		e.Draw(win, es, worldToScreen)
+		if isVisitedByParent[Drawer](es, e) {
+			continue
+		}
+		es.drawTree(win, e, worldToScreen)
 	}
 }
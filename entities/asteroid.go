@@ -10,30 +10,30 @@ import (
 )
 
 func NewAsteroid(typ AsteroidType) *Asteroid {
-	var batchName, tagName string
+	var batchQuery *ent.Query
+	var tagName string
 	var sprite *pixel.Sprite
 	var resources int
 	radius := rand.Float64()*1.5 + 0.5
 	switch typ {
 	case NormalAsteroid:
-		batchName = "asteroid_batch"
+		batchQuery = asteroidBatchQuery
 		tagName = "asteroid"
 		sprite = GlobalSpriteManager.FullSprite("asteroid.png")
 		resources = 0
 	case MineableAsteroid:
-		batchName = "mineable_asteroid_batch"
+		batchQuery = mineableAsteroidBatchQuery
 		tagName = "mineable_asteroid"
 		sprite = GlobalSpriteManager.FullSprite("asteroid-mineable.png")
 		resources = int(radius * 3)
 	}
 	ast := &Asteroid{
-		sprite:    sprite,
-		velocity:  pixel.V(0.5, 0).Rotated(rand.Float64() * math.Pi * 2),
-		radius:    radius,
-		batchName: batchName,
-		tagName:   tagName,
-		resources: resources,
-		toMiners:  ent.NewBus(),
+		sprite:     sprite,
+		velocity:   pixel.V(0.5, 0).Rotated(rand.Float64() * math.Pi * 2),
+		radius:     radius,
+		batchQuery: batchQuery,
+		tagName:    tagName,
+		resources:  resources,
 	}
 	ast.SetPosition(pixel.V(rand.Float64()*100, rand.Float64()*100))
 	return ast
@@ -51,13 +51,12 @@ type Asteroid struct {
 	ent.WithActivePhysics
 	ent.WithUpdate
 	ent.WithDraw
-	batchName string
-	tagName   string
-	sprite    *pixel.Sprite
-	velocity  pixel.Vec
-	radius    float64
-	resources int
-	toMiners  *ent.Bus
+	batchQuery *ent.Query
+	tagName    string
+	sprite     *pixel.Sprite
+	velocity   pixel.Vec
+	radius     float64
+	resources  int
 }
 
 // Shape implements ent.ActivePhysicsBody.
@@ -76,13 +75,12 @@ func (a *Asteroid) AfterAdd(w *ent.World) {
 	w.AddTags(a, a.tagName)
 }
 
+// Asteroids only read the player's position when updating.
+func (a *Asteroid) ParallelSafe() {}
+
 func (a *Asteroid) Update(win *pixelgl.Window, entities *ent.World, dt float64) {
 	// Check if out of range of player, and delete if so
-	player, ok := ent.First(
-		ent.OfType[*Player](
-			entities.WithTag("player"),
-		),
-	)
+	player, ok := findPlayer(entities)
 	if ok {
 		dist := player.Position().To(a.Position()).Len()
 		if dist > 40 {
@@ -93,11 +91,7 @@ func (a *Asteroid) Update(win *pixelgl.Window, entities *ent.World, dt float64)
 }
 
 func (a *Asteroid) Draw(win *pixelgl.Window, world *ent.World, worldToScreen pixel.Matrix) {
-	batch, ok := ent.First(
-		ent.OfType[*BatchDraw](
-			world.WithTag(a.batchName),
-		),
-	)
+	batch, ok := ent.FindFirst[*BatchDraw](world, a.batchQuery)
 	if !ok {
 		return
 	}
@@ -114,16 +108,13 @@ func (a *Asteroid) Draw(win *pixelgl.Window, world *ent.World, worldToScreen pix
 	)
 }
 
-func (a *Asteroid) ToMiners() *ent.Bus { return a.toMiners }
-
 type MineAsteroid struct {
 	From pixel.Vec
 }
 
 type AsteroidDestroyed struct{}
 
-type AsteroidOutOfRange struct{}
-
+// Asks an asteroid whether it is out of mining range, replying with a bool.
 type CheckOutOfMiningRange struct {
 	From    pixel.Vec
 	MaxDist float64
@@ -135,17 +126,20 @@ func (a *Asteroid) HandleMessage(world *ent.World, msg any) {
 		a.resources--
 		destroy := a.resources <= 0
 		if destroy {
-			ent.Emit(world, a.toMiners, AsteroidDestroyed{})
+			ent.EmitLater(world, world.Topic("asteroid.destroyed"), AsteroidDestroyed{})
 			world.Remove(a)
 			world.Add(NewExplosion(a.Position(), a.Radius()))
 		} else {
 			edgePos := a.Position().To(msg.From).Unit().Scaled(a.radius).Add(a.Position())
 			world.Add(NewExplosion(edgePos, 0.3))
 		}
+	}
+}
+
+func (a *Asteroid) HandleQuery(world *ent.World, query any) (any, bool) {
+	switch query := query.(type) {
 	case CheckOutOfMiningRange:
-		if a.Position().To(msg.From).Len() > msg.MaxDist {
-			ent.Emit(world, a.toMiners, AsteroidOutOfRange{})
-			ent.UnsubscribeAll(a.toMiners)
-		}
+		return a.Position().To(query.From).Len() > query.MaxDist, true
 	}
+	return nil, false
 }
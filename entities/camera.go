@@ -13,7 +13,6 @@ func NewCamera() *Camera {
 
 type Camera struct {
 	ent.CoreEntity
-	ent.WithUpdate
 	pos pixel.Vec
 }
 
@@ -29,20 +28,11 @@ func (c *Camera) AfterAdd(world *ent.World) {
 	world.AddTags(c, "camera")
 }
 
-// Update implements ent.Entity.
-func (c *Camera) Update(win *pixelgl.Window, all *ent.World, dt float64) {
-	target, ok := ent.First(
-		ent.OfType[CameraTarget](
-			all.WithTag("player_camera_target"),
-		),
-	)
+// Follow the target once physics has moved it this frame.
+func (c *Camera) LateUpdate(win *pixelgl.Window, all *ent.World, dt float64) {
+	target, ok := ent.FindFirst[CameraTarget](all, cameraTargetQuery)
 	if !ok {
 		target = c
 	}
-	c.pos = pixel.Lerp(c.pos, target.Position(), 0.05)
-}
-
-// UpdateLayer implements ent.Entity.
-func (c *Camera) UpdateLayer() int {
-	return 5
+	c.pos = ent.DampVec(c.pos, target.Position(), 0.05, dt)
 }
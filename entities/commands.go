@@ -0,0 +1,210 @@
+package entities
+
+import (
+	"ent"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gopxl/pixel"
+)
+
+var spawnables = map[string]func(pos pixel.Vec) ent.Entity{
+	"asteroid": func(pos pixel.Vec) ent.Entity {
+		a := NewAsteroid(NormalAsteroid)
+		a.SetPosition(pos)
+		return a
+	},
+	"mineable_asteroid": func(pos pixel.Vec) ent.Entity {
+		a := NewAsteroid(MineableAsteroid)
+		a.SetPosition(pos)
+		return a
+	},
+	"enemy": func(pos pixel.Vec) ent.Entity {
+		e := NewEnemy()
+		e.SetPosition(pos)
+		return e
+	},
+	"explosion": func(pos pixel.Vec) ent.Entity {
+		return NewExplosion(pos, 1)
+	},
+	"stasis_field": func(pos pixel.Vec) ent.Entity {
+		return NewStasisField(pos)
+	},
+}
+
+// Make an entity available to the spawn console command under the given name.
+func RegisterSpawnable(name string, spawn func(pos pixel.Vec) ent.Entity) {
+	spawnables[name] = spawn
+}
+
+// Add count of the named spawnable to the world at the position, see RegisterSpawnable.
+func Spawn(w *ent.World, name string, at pixel.Vec, count int) (string, error) {
+	spawn, ok := spawnables[name]
+	if !ok {
+		return "", fmt.Errorf("nothing called %q can be spawned", name)
+	}
+	for range count {
+		w.Add(spawn(at))
+	}
+	return fmt.Sprintf("spawned %d %s", count, name), nil
+}
+
+func init() {
+	ent.RegisterCommand(ent.Command{
+		Name:  "spawn",
+		Usage: "<name> [count]",
+		Help:  "Spawn entities at the cursor",
+		Run: func(ctx ent.CommandContext, args []string) (string, error) {
+			if len(args) < 1 || len(args) > 2 {
+				return "", fmt.Errorf("usage: spawn <name> [count], names: %s", strings.Join(slices.Sorted(maps.Keys(spawnables)), ", "))
+			}
+			count := 1
+			if len(args) == 2 {
+				var err error
+				if count, err = strconv.Atoi(args[1]); err != nil || count < 1 {
+					return "", fmt.Errorf("count must be a positive number, not %q", args[1])
+				}
+			}
+			if ctx.World == nil {
+				return "", ent.ErrNoWorld
+			}
+			return Spawn(ctx.World, args[0], ctx.Cursor, count)
+		},
+	})
+	ent.RegisterCommand(ent.Command{
+		Name:  "shields",
+		Usage: "<amount>",
+		Help:  "Set the player's shields",
+		Run: setPlayerStat("shields", func(p *Player, n int) {
+			p.sheilds = n
+		}),
+	})
+	ent.RegisterCommand(ent.Command{
+		Name:  "minerals",
+		Usage: "<amount>",
+		Help:  "Set the player's minerals",
+		Run: setPlayerStat("minerals", func(p *Player, n int) {
+			p.minerals = n
+		}),
+	})
+	ent.RegisterCommand(ent.Command{
+		Name: "god",
+		Help: "Toggle whether the player can take damage",
+		Run: func(ctx ent.CommandContext, args []string) (string, error) {
+			player, err := commandPlayer(ctx)
+			if err != nil {
+				return "", err
+			}
+			player.god = !player.god
+			if player.god {
+				return "god mode on", nil
+			}
+			return "god mode off", nil
+		},
+	})
+	ent.RegisterCommand(ent.Command{
+		Name:  "list",
+		Usage: "[tag]",
+		Help:  "List entities with the tag, or all entities",
+		Run: func(ctx ent.CommandContext, args []string) (string, error) {
+			if len(args) > 1 {
+				return "", errors.New("usage: list [tag]")
+			}
+			if ctx.World == nil {
+				return "", ent.ErrNoWorld
+			}
+			q := ent.NewQuery()
+			if len(args) == 1 {
+				q = q.AllOf(args[0])
+			}
+			lines := make([]string, 0)
+			for e := range ent.Find[ent.Entity](ctx.World, q) {
+				lines = append(lines, fmt.Sprintf("%v %T", e.UUID(), e))
+			}
+			return fmt.Sprintf("%d entities\n%s", len(lines), strings.Join(lines, "\n")), nil
+		},
+	})
+	ent.RegisterCommand(ent.Command{
+		Name:  "dump",
+		Usage: "<id>",
+		Help:  "Show the fields of an entity",
+		Run: func(ctx ent.CommandContext, args []string) (string, error) {
+			if len(args) != 1 {
+				return "", errors.New("usage: dump <id>")
+			}
+			if ctx.World == nil {
+				return "", ent.ErrNoWorld
+			}
+			id, err := ent.ParseEntityUUID(args[0])
+			if err != nil {
+				return "", err
+			}
+			e, ok := ctx.World.WithUUID(id)
+			if !ok {
+				return "", fmt.Errorf("no entity %v in the world", id)
+			}
+			return fmt.Sprintf("%T %+v", e, e), nil
+		},
+	})
+	ent.RegisterCommand(ent.Command{
+		Name:  "teleport",
+		Usage: "[x y]",
+		Help:  "Move the player to the position, or to the cursor",
+		Run: func(ctx ent.CommandContext, args []string) (string, error) {
+			pos := ctx.Cursor
+			switch len(args) {
+			case 0:
+			case 2:
+				x, errX := strconv.ParseFloat(args[0], 64)
+				y, errY := strconv.ParseFloat(args[1], 64)
+				if errX != nil || errY != nil {
+					return "", errors.New("x and y must be numbers")
+				}
+				pos = pixel.V(x, y)
+			default:
+				return "", errors.New("usage: teleport [x y]")
+			}
+			player, err := commandPlayer(ctx)
+			if err != nil {
+				return "", err
+			}
+			player.SetPosition(pos)
+			player.SetVelocity(pixel.ZV)
+			return fmt.Sprintf("teleported to %.1f, %.1f", pos.X, pos.Y), nil
+		},
+	})
+}
+
+func commandPlayer(ctx ent.CommandContext) (*Player, error) {
+	if ctx.World == nil {
+		return nil, ent.ErrNoWorld
+	}
+	player, ok := findPlayer(ctx.World)
+	if !ok {
+		return nil, errors.New("there is no player")
+	}
+	return player, nil
+}
+
+// Make a command that sets one of the player's stats to its argument.
+func setPlayerStat(name string, set func(*Player, int)) func(ent.CommandContext, []string) (string, error) {
+	return func(ctx ent.CommandContext, args []string) (string, error) {
+		if len(args) != 1 {
+			return "", fmt.Errorf("usage: %s <amount>", name)
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return "", fmt.Errorf("amount must be a whole number, not %q", args[0])
+		}
+		player, err := commandPlayer(ctx)
+		if err != nil {
+			return "", err
+		}
+		set(player, n)
+		return fmt.Sprintf("%s set to %d", name, n), nil
+	}
+}
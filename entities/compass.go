@@ -42,17 +42,16 @@ func (c *Compass) DrawLayer() int {
 
 // Update implements ent.Entity.
 func (c *Compass) Update(win *pixelgl.Window, all *ent.World, dt float64) {
-	player, ok := ent.First(
-		ent.OfType[*Player](
-			all.WithTag("player"),
-		),
-	)
+	player, ok := findPlayer(all)
 	if !ok {
 		return
 	}
 	c.angle = player.Position().Angle()
 }
 
+// The compass keeps pointing while the world is paused or slowed down.
+func (c *Compass) UnscaledTime() {}
+
 // UpdateLayer implements ent.Entity.
 func (c *Compass) UpdateLayer() int {
 	return -10
@@ -56,6 +56,9 @@ func (e *Explosion) Draw(win *pixelgl.Window, _ *ent.World, worldToScreen pixel.
 
 func (e *Explosion) DrawLayer() int { return -1 }
 
+// Explosions only change their own timer when updating.
+func (e *Explosion) ParallelSafe() {}
+
 // Update implements ent.Entity.
 func (e *Explosion) Update(win *pixelgl.Window, all *ent.World, dt float64) {
 	e.timer += dt
@@ -3,6 +3,7 @@ package entities
 import (
 	"ent"
 	"fmt"
+	"math"
 
 	_ "embed"
 
@@ -18,11 +19,7 @@ var mainFont []byte
 
 func NewSheildsIndicator() *statsIndicator {
 	return NewStatsIndicator("bubble.png", 150, func(w *ent.World) int {
-		player, ok := ent.First(
-			ent.OfType[*Player](
-				w.WithTag("player"),
-			),
-		)
+		player, ok := findPlayer(w)
 		if !ok {
 			return 0
 		}
@@ -32,11 +29,7 @@ func NewSheildsIndicator() *statsIndicator {
 
 func NewMineralsIndicator() *statsIndicator {
 	return NewStatsIndicator("minerals.png", 200, func(w *ent.World) int {
-		player, ok := ent.First(
-			ent.OfType[*Player](
-				w.WithTag("player"),
-			),
-		)
+		player, ok := findPlayer(w)
 		if !ok {
 			return 0
 		}
@@ -44,6 +37,29 @@ func NewMineralsIndicator() *statsIndicator {
 	})
 }
 
+func NewAsteroidsIndicator() *statsIndicator {
+	return NewStatsIndicator("asteroid-mineable.png", 250, func(w *ent.World) int {
+		player, ok := findPlayer(w)
+		if !ok {
+			return 0
+		}
+		return player.AsteroidsDestroyed()
+	})
+}
+
+// Shows how many seconds until the player can deploy another stasis field, tinted like the field.
+func NewStasisIndicator() *statsIndicator {
+	sa := NewStatsIndicator("bubble.png", 300, func(w *ent.World) int {
+		player, ok := findPlayer(w)
+		if !ok {
+			return 0
+		}
+		return int(math.Ceil(player.StasisCooldown(w)))
+	})
+	sa.mask = pixel.RGB(0.4, 0.7, 1)
+	return sa
+}
+
 func NewStatsIndicator(spriteName string, vPos float64, get func(*ent.World) int) *statsIndicator {
 	sprite := GlobalSpriteManager.FullSprite(spriteName)
 	sa := &statsIndicator{
@@ -51,6 +67,7 @@ func NewStatsIndicator(spriteName string, vPos float64, get func(*ent.World) int
 		text:   text.New(pixel.ZV, sheildsAtlas()).AlignedTo(pixel.Right),
 		vPos:   vPos,
 		get:    get,
+		mask:   pixel.Alpha(1),
 	}
 	sa.text.Color = colornames.Skyblue
 	return sa
@@ -65,20 +82,25 @@ type statsIndicator struct {
 	text   *text.Text
 	get    func(*ent.World) int
 	vPos   float64
+	mask   pixel.RGBA
 }
 
+// Stats stay current while the world is paused or slowed down.
+func (c *statsIndicator) UnscaledTime() {}
+
 func (c *statsIndicator) Update(win *pixelgl.Window, all *ent.World, dt float64) {
 	c.value = c.get(all)
 }
 
 func (c *statsIndicator) Draw(win *pixelgl.Window, _ *ent.World, worldToScreen pixel.Matrix) {
-	c.sprite.Draw(
+	c.sprite.DrawColorMask(
 		win,
 		pixel.IM.Scaled(
 			pixel.ZV, 30.0/c.sprite.Frame().W(),
 		).Moved(
 			pixel.V(30, c.vPos),
 		),
+		c.mask,
 	)
 	c.text.Clear()
 	fmt.Fprintf(c.text, "%d", c.value)
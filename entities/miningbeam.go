@@ -7,11 +7,18 @@ import (
 	"github.com/gopxl/pixel/pixelgl"
 )
 
-func NewMiningBeam(startUUID, endUUID ent.EntityUUID) *MiningBeam {
+type beamTarget interface {
+	ent.EntityUUIDer
+	ent.Transform
+}
+
+// Create a mining beam to the end entity.
+// The beam starts at its parent (see ent.World.SetParent), and removes itself once the end entity has gone.
+func NewMiningBeam(end beamTarget) *MiningBeam {
 	return &MiningBeam{
-		sprite:  GlobalSpriteManager.FullSprite("tether.png"),
-		startID: startUUID,
-		endID:   endUUID,
+		sprite: GlobalSpriteManager.FullSprite("tether.png"),
+		end:    ent.RefTo(end),
+		endPos: end.Position(),
 	}
 }
 
@@ -19,37 +26,32 @@ type MiningBeam struct {
 	ent.CoreEntity
 	ent.WithUpdate
 	ent.WithDraw
+	ent.WithLocalTransform
 	sprite   *pixel.Sprite
-	startID  ent.EntityUUID
-	endID    ent.EntityUUID
-	startPos pixel.Vec
+	end      ent.Ref[beamTarget]
 	endPos   pixel.Vec
 	inverted bool
-	timer    float64
 	destroy  bool
 }
 
+func (e *MiningBeam) AfterAdd(world *ent.World) {
+	world.EveryFor(e, 0.2, func() {
+		e.inverted = !e.inverted
+	})
+}
+
 func (e *MiningBeam) Update(win *pixelgl.Window, world *ent.World, dt float64) {
-	start, okStart := ent.OneOfType[ent.Transform](world.WithUUID(e.startID))
-	if okStart {
-		e.startPos = start.Position()
-	}
-	end, okEnd := ent.OneOfType[ent.Transform](world.WithUUID(e.endID))
-	if okEnd {
+	if end, ok := e.end.Get(world); ok {
 		e.endPos = end.Position()
 	}
-	e.timer += dt
-	if e.timer > 0.2 {
-		e.timer = 0
-		e.inverted = !e.inverted
-	}
-	if e.destroy {
+	if e.destroy || e.end.Removed(world) {
 		world.Remove(e)
 	}
 }
 
 func (e *MiningBeam) Draw(win *pixelgl.Window, _ *ent.World, worldToScreen pixel.Matrix) {
-	dist := e.startPos.To(e.endPos).Len()
+	startPos := e.Position()
+	dist := startPos.To(e.endPos).Len()
 	if dist == 0 {
 		return
 	}
@@ -63,8 +65,8 @@ func (e *MiningBeam) Draw(win *pixelgl.Window, _ *ent.World, worldToScreen pixel
 			Scaled(pixel.ZV, 1.0/16.0).
 			Moved(pixel.V(0.5, 0)).
 			ScaledXY(pixel.ZV, pixel.V(dist, yScale)).
-			Rotated(pixel.ZV, e.startPos.To(e.endPos).Angle()).
-			Moved(e.startPos).
+			Rotated(pixel.ZV, startPos.To(e.endPos).Angle()).
+			Moved(startPos).
 			Chained(worldToScreen),
 	)
 }
@@ -8,6 +8,9 @@ import (
 	"github.com/gopxl/pixel/pixelgl"
 )
 
+// How long the player has to wait between deploying stasis fields, in seconds.
+const stasisFieldCooldown = 10
+
 func NewPlayer() *Player {
 	shipSprite := GlobalSpriteManager.FullSprite("ship.png")
 	bubbleSprite := GlobalSpriteManager.FullSprite("bubble.png")
@@ -21,7 +24,6 @@ func NewPlayer() *Player {
 		bubbleSprite:     bubbleSprite,
 		sheilds:          3,
 		toMiningBeams:    ent.NewBus(),
-		toAsteroids:      ent.NewBus(),
 	}
 }
 
@@ -42,21 +44,33 @@ type Player struct {
 
 	lastDamageTimer float64
 	bubbleTimer     float64
-	miningTimer     float64
+	stasisCooldown  *ent.Timer
+
+	sheilds            int
+	dead               bool
+	minerals           int
+	asteroidsDestroyed int
+	mining             bool
+	god                bool
 
-	sheilds  int
-	dead     bool
-	minerals int
-	mining   bool
+	miningTarget ent.Ref[*Asteroid]
+	miningTicker *ent.Timer
 
 	lastfx ent.BodyEffects
 
 	toMiningBeams *ent.Bus
-	toAsteroids   *ent.Bus
 }
 
 func (p *Player) AfterAdd(w *ent.World) {
 	w.AddTags(p, "player", "player_camera_target")
+	ent.Subscribe(w.Topic("asteroid.destroyed"), p)
+}
+
+func (p *Player) HandleMessage(world *ent.World, msg any) {
+	switch msg.(type) {
+	case AsteroidDestroyed:
+		p.asteroidsDestroyed++
+	}
 }
 
 func (p *Player) Update(win *pixelgl.Window, world *ent.World, dt float64) {
@@ -71,11 +85,16 @@ func (p *Player) Update(win *pixelgl.Window, world *ent.World, dt float64) {
 			p.startMining(world, asteroid)
 		}
 	} else if win.JustReleased(pixelgl.KeySpace) {
-		p.stopMining()
+		p.stopMining(world)
 	}
 
 	if p.mining {
-		p.handleMining(world, dt)
+		p.checkMiningRange(world)
+	}
+
+	if win.JustPressed(pixelgl.KeyF) && (p.stasisCooldown == nil || !p.stasisCooldown.Active()) {
+		world.Add(NewStasisField(p.Position()))
+		p.stasisCooldown = world.AfterFor(p, stasisFieldCooldown, func() {})
 	}
 
 	fx := ent.BodyEffects{}
@@ -97,12 +116,8 @@ func (p *Player) Update(win *pixelgl.Window, world *ent.World, dt float64) {
 	p.bubbleTimer -= dt
 }
 
-func (p *Player) HandleMessage(world *ent.World, msg any) {
-	switch msg.(type) {
-	case AsteroidDestroyed, AsteroidOutOfRange:
-		p.stopMining()
-	}
-}
+// The player always moves at normal speed, even inside their own stasis fields.
+func (p *Player) IgnoreTimeZones() {}
 
 func (p *Player) PysicsUpdate(dt float64) {
 	ent.EulerStateUpdate(p, p.lastfx, dt)
@@ -138,6 +153,9 @@ func (p *Player) Draw(win *pixelgl.Window, _ *ent.World, worldToScreen pixel.Mat
 }
 
 func (p *Player) OnCollision(col ent.Collision) {
+	if p.god {
+		return
+	}
 	if p.sheilds <= 0 {
 		p.dead = true
 		return
@@ -156,46 +174,53 @@ func (p *Player) destroy(world *ent.World) {
 		NewPlayer(),
 	)
 	world.Remove(p)
-	ent.Emit(world, p.toMiningBeams, MiningBeamOff{})
+}
+
+func (p *Player) BeforeRemove(world *ent.World) {
+	p.stopMining(world)
 }
 
 func (p *Player) startMining(world *ent.World, asteroid *Asteroid) {
-	beam := NewMiningBeam(p.UUID(), asteroid.UUID())
+	beam := NewMiningBeam(asteroid)
 	world.Add(beam)
+	world.SetParent(beam, p)
 	ent.Subscribe(p.toMiningBeams, beam)
-	ent.Subscribe(p.toAsteroids, asteroid)
-	ent.Subscribe(asteroid.ToMiners(), p)
-	p.miningTimer = 0
+	p.miningTarget = ent.RefTo(asteroid)
+	p.miningTicker = world.EveryFor(p, 1, func() {
+		p.mine(world)
+	})
 	p.mining = true
 }
 
-func (p *Player) stopMining() {
-	ent.UnsubscribeAll(p.toAsteroids)
-	ent.Emit(nil, p.toMiningBeams, MiningBeamOff{})
+func (p *Player) stopMining(world *ent.World) {
+	ent.Emit(world, p.toMiningBeams, MiningBeamOff{})
+	if p.miningTicker != nil {
+		p.miningTicker.Cancel()
+	}
+	p.miningTarget = ent.Ref[*Asteroid]{}
 	p.mining = false
 }
-func (p *Player) handleMining(world *ent.World, dt float64) {
-	p.miningTimer += dt
-	ent.Emit(world, p.toAsteroids, CheckOutOfMiningRange{
+
+func (p *Player) checkMiningRange(world *ent.World) {
+	// Stop if the asteroid has gone (it will not reply) or drifted out of range
+	outOfRange, ok := ent.Ask[bool](world, p.miningTarget, CheckOutOfMiningRange{
 		From:    p.Position(),
 		MaxDist: 10,
 	})
-	if p.miningTimer > 1 {
-		p.miningTimer = 0
-		p.minerals++
-		ent.Emit(world, p.toAsteroids, MineAsteroid{
-			p.Position(),
-		})
+	if !ok || outOfRange {
+		p.stopMining(world)
 	}
 }
 
-func (p *Player) selectClosestAsteroid(entities *ent.World) (*Asteroid, bool) {
-	return ent.Closest(
+func (p *Player) mine(world *ent.World) {
+	p.minerals++
+	ent.EmitDirectly(world, MineAsteroid{
 		p.Position(),
-		ent.OfType[*Asteroid](
-			entities.WithTag("mineable_asteroid"),
-		),
-	)
+	}, p.miningTarget)
+}
+
+func (p *Player) selectClosestAsteroid(entities *ent.World) (*Asteroid, bool) {
+	return ent.FindFirst[*Asteroid](entities, mineableAsteroidQuery.NearestTo(p.Position()))
 }
 
 func (p *Player) Shields() int {
@@ -206,6 +231,19 @@ func (p *Player) Minerals() int {
 	return p.minerals
 }
 
+// Get how many asteroids have been mined until they broke up.
+func (p *Player) AsteroidsDestroyed() int {
+	return p.asteroidsDestroyed
+}
+
+// Get how many seconds until the player can deploy another stasis field.
+func (p *Player) StasisCooldown(w *ent.World) float64 {
+	if p.stasisCooldown == nil || !p.stasisCooldown.Active() {
+		return 0
+	}
+	return math.Max(p.stasisCooldown.NextRun()-w.Time(), 0)
+}
+
 func (p *Player) Radius() float64 {
 	return p.radius
 }
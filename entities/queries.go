@@ -0,0 +1,17 @@
+package entities
+
+import "ent"
+
+var (
+	playerQuery           = ent.NewQuery().AllOf("player").Cached()
+	cameraTargetQuery     = ent.NewQuery().AllOf("player_camera_target").Cached()
+	mineableAsteroidQuery = ent.NewQuery().AllOf("mineable_asteroid").Cached()
+	// The batches that draw each type of asteroid, shared by every asteroid of that type.
+	asteroidBatchQuery         = ent.NewQuery().AllOf("asteroid_batch").Cached()
+	mineableAsteroidBatchQuery = ent.NewQuery().AllOf("mineable_asteroid_batch").Cached()
+)
+
+// Get the player, if there is one in the world.
+func findPlayer(w *ent.World) (*Player, bool) {
+	return ent.FindFirst[*Player](w, playerQuery)
+}
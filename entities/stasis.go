@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"ent"
+	"math"
+
+	"github.com/gopxl/pixel"
+	"github.com/gopxl/pixel/imdraw"
+	"github.com/gopxl/pixel/pixelgl"
+)
+
+// How long a stasis field lasts, in seconds of world time.
+const stasisFieldLifetime = 6
+
+func NewStasisField(at pixel.Vec) *StasisField {
+	s := &StasisField{
+		imd:    imdraw.New(nil),
+		radius: 6,
+		scale:  0.15,
+	}
+	s.SetPosition(at)
+	return s
+}
+
+var _ ent.TimeZone = &StasisField{}
+
+// A bubble that slows down time for everything inside it, then pops after a while.
+type StasisField struct {
+	ent.CoreEntity
+	ent.WithTransform
+	ent.WithDraw
+	imd    *imdraw.IMDraw
+	radius float64
+	scale  float64
+	// Fields all have the same priority, so where they overlap their time scales multiply.
+	priority int
+	expiry   *ent.Timer
+}
+
+func (s *StasisField) AfterAdd(w *ent.World) {
+	w.AddTags(s, "stasis_field")
+	s.expiry = w.AfterFor(s, stasisFieldLifetime, func() {
+		w.Remove(s)
+	})
+}
+
+// ZoneShape implements ent.TimeZone.
+func (s *StasisField) ZoneShape() ent.Shape {
+	return ent.Circle{
+		Center: s.Position(),
+		Radius: s.radius,
+	}
+}
+
+// ZoneTimeScale implements ent.TimeZone.
+func (s *StasisField) ZoneTimeScale() float64 {
+	return s.scale
+}
+
+// ZonePriority implements ent.TimeZone.
+func (s *StasisField) ZonePriority() int {
+	return s.priority
+}
+
+func (s *StasisField) Draw(win *pixelgl.Window, world *ent.World, worldToScreen pixel.Matrix) {
+	// Fade out over the last second
+	fade := 1.0
+	if s.expiry != nil {
+		fade = math.Max(0, math.Min(1, s.expiry.NextRun()-world.Time()))
+	}
+	s.imd.Clear()
+	s.imd.SetMatrix(worldToScreen)
+	s.imd.Color = pixel.RGB(0.4, 0.7, 1).Mul(pixel.Alpha(0.15 * fade))
+	s.imd.Push(s.Position())
+	s.imd.Circle(s.radius, 0)
+	s.imd.Color = pixel.RGB(0.4, 0.7, 1).Mul(pixel.Alpha(0.6 * fade))
+	s.imd.Push(s.Position())
+	s.imd.Circle(s.radius, 0.1)
+	s.imd.Draw(win)
+}
+
+func (s *StasisField) DrawLayer() int { return -1 }
@@ -21,23 +21,22 @@ func NewStation() *Station {
 type Station struct {
 	ent.CoreEntity
 	ent.WithDraw
-	sprites     []*pixel.Sprite
-	spriteTimer float64
+	sprites   []*pixel.Sprite
+	spriteIdx int
+}
+
+func (s *Station) AfterAdd(world *ent.World) {
+	world.EveryFor(s, 2, func() {
+		s.spriteIdx = (s.spriteIdx + 1) % len(s.sprites)
+	})
 }
 
 // Draw implements ent.Entity.
 func (s *Station) Draw(win *pixelgl.Window, _ *ent.World, worldToScreen pixel.Matrix) {
-	spriteIdx := int(s.spriteTimer*0.5) % len(s.sprites)
-	s.sprites[spriteIdx].Draw(win, pixel.IM.Scaled(pixel.ZV, 0.1).Chained(worldToScreen))
+	s.sprites[s.spriteIdx].Draw(win, pixel.IM.Scaled(pixel.ZV, 0.1).Chained(worldToScreen))
 }
 
 // DrawLayer implements ent.Entity.
 func (s *Station) DrawLayer() int {
 	return 1
 }
-
-// Update implements ent.Entity.
-func (s *Station) Update(win *pixelgl.Window, all *ent.World, dt float64) (toCreate []ent.Entity, toDestroy []ent.Entity) {
-	s.spriteTimer += dt
-	return nil, nil
-}
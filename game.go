@@ -8,8 +8,11 @@ import (
 	"github.com/gopxl/pixel/pixelgl"
 )
 
-func NewGame() Screen {
+var cameraQuery = ent.NewQuery().AllOf("camera").Cached()
+
+func NewGame() *Game {
 	world := ent.NewWorld()
+	physicsDebug := ent.NewPhysicsDebugDraw()
 	world.AddNow(
 		entities.NewCamera(),
 		entities.NewStation(),
@@ -21,35 +24,51 @@ func NewGame() Screen {
 		entities.NewAsteroidSpawner(),
 		entities.NewSheildsIndicator(),
 		entities.NewMineralsIndicator(),
+		entities.NewAsteroidsIndicator(),
+		entities.NewStasisIndicator(),
 		entities.NewEnemy(),
+		physicsDebug,
 	)
 	return &Game{
-		world: world,
+		world:        world,
+		physicsDebug: physicsDebug,
 	}
 }
 
 type Game struct {
-	world *ent.World
+	world        *ent.World
+	physicsDebug *ent.PhysicsDebugDraw
 }
 
-func (g *Game) Update(win *pixelgl.Window, dt float64) Screen {
+func (g *Game) Update(win *pixelgl.Window, dt float64) SceneOp {
+	if win.JustPressed(pixelgl.KeyEscape) {
+		return Push(NewPauseMenu())
+	}
+	if win.JustPressed(pixelgl.KeyF3) {
+		g.physicsDebug.Toggle()
+	}
 	g.world.Update(win, dt)
 	return nil
 }
 
 func (g *Game) Draw(win *pixelgl.Window) {
+	// Draw all objects
+	win.Clear(pixel.RGB(0.01, 0.01, 0.05))
+	g.world.Draw(win, g.WorldToScreen(win))
+}
+
+// World implements WorldScreen.
+func (g *Game) World() *ent.World {
+	return g.world
+}
+
+// WorldToScreen implements WorldScreen.
+func (g *Game) WorldToScreen(win *pixelgl.Window) pixel.Matrix {
 	// Get matrix to transform workd to screen pos
 	camMat := pixel.IM.Scaled(pixel.ZV, 20).Moved(win.Bounds().Center())
-	camera, ok := ent.First(
-		ent.OfType[entities.CameraTarget](
-			g.world.WithTag("camera"),
-		),
-	)
+	camera, ok := ent.FindFirst[entities.CameraTarget](g.world, cameraQuery)
 	if ok {
 		camMat = pixel.IM.Moved(camera.Position().Scaled(-1)).Chained(camMat)
 	}
-
-	// Draw all objects
-	win.Clear(pixel.RGB(0.01, 0.01, 0.05))
-	g.world.Draw(win, camMat)
+	return camMat
 }
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"ent"
+	"flag"
 	"te2/entities"
 
 	"github.com/gopxl/pixel"
 	"github.com/gopxl/pixel/pixelgl"
 )
 
+var debugAddr = flag.String("debug-addr", "", "serve world state as JSON on this localhost address, such as localhost:6060")
+
 func main() {
+	flag.Parse()
 	pixelgl.Run(run)
 }
 
@@ -24,16 +29,39 @@ func run() {
 		panic(err)
 	}
 
-	var screen Screen
-	screen = NewMenu()
+	stack := NewSceneStack(NewMenu())
+	console := NewConsole()
 
-	for !win.Closed() {
-		newScreen := screen.Update(win, 1.0/60.0)
-		screen.Draw(win)
+	var debug *ent.DebugServer
+	if *debugAddr != "" {
+		debug = ent.NewDebugServer(ent.DebugServerOptions{
+			Spawn:   entities.Spawn,
+			Profile: true,
+		})
+		if err := debug.Listen(*debugAddr); err != nil {
+			panic(err)
+		}
+		defer debug.Close()
+	}
 
-		if newScreen != nil {
-			screen = newScreen
+	// Popping the last screen, such as pressing escape on the menu, quits
+	for !win.Closed() && !stack.Empty() {
+		if win.JustPressed(pixelgl.KeyGraveAccent) {
+			console.Toggle(stack)
+		}
+		if debug != nil {
+			debug.Service(shownWorld(stack))
 		}
+		stack.Update(win, 1.0/60.0)
+		stack.Draw(win)
 		win.Update()
 	}
 }
+
+// Get the world of the highest screen in the stack that shows one, if there is one.
+func shownWorld(stack *SceneStack) *ent.World {
+	if ws, ok := shownWorldScreen(stack); ok {
+		return ws.World()
+	}
+	return nil
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	_ "embed"
-	"math"
+	"ent"
 
 	"github.com/golang/freetype/truetype"
 	"github.com/gopxl/pixel"
@@ -47,17 +47,21 @@ func NewMenu() Screen {
 	infoText.Clear()
 	infoText.Write([]byte("Space to Play\n\nEscape to Quit"))
 
-	return &Menu{
+	m := &Menu{
 		titleText: titleText,
 		infoText:  infoText,
 	}
+	m.titleWobble = ent.NewTweenPlayer(ent.Loop(ent.Yoyo(
+		ent.TweenFloat(-0.05, 0.05, 2, ent.EaseInOutSine, func(r float64) { m.titleRotation = r }),
+	), 0))
+	return m
 }
 
 type Menu struct {
 	titleText     *text.Text
 	infoText      *text.Text
 	titleRotation float64
-	timer         float64
+	titleWobble   *ent.TweenPlayer
 }
 
 // Draw implements Screen.
@@ -83,11 +87,19 @@ func (m *Menu) Draw(win *pixelgl.Window) {
 }
 
 // Update implements Screen.
-func (m *Menu) Update(win *pixelgl.Window, dt float64) Screen {
+func (m *Menu) Update(win *pixelgl.Window, dt float64) SceneOp {
 	if win.JustPressed(pixelgl.KeySpace) {
-		return NewGame()
+		return WithTransition(Push(NewGame()), FadeTo(colornames.Black, 0.8, ent.EaseInOutQuad))
 	}
-	m.timer += dt
-	m.titleRotation = math.Sin(m.timer*1.5) * 0.05
+	// The menu is the bottom of the stack, so popping it empties the stack, which quits the game as the menu says
+	if win.JustPressed(pixelgl.KeyEscape) {
+		return Pop()
+	}
+	m.titleWobble.Update(dt)
 	return nil
 }
+
+func isMenu(s Screen) bool {
+	_, ok := s.(*Menu)
+	return ok
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"ent"
+
+	"github.com/gopxl/pixel"
+	"github.com/gopxl/pixel/imdraw"
+	"github.com/gopxl/pixel/pixelgl"
+	"github.com/gopxl/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+func NewPauseMenu() *PauseMenu {
+	titleText := text.New(pixel.ZV, titleAtlas()).AlignedTo(pixel.Center)
+	titleText.Color = colornames.White
+	titleText.Clear()
+	titleText.Write([]byte("Paused"))
+
+	infoText := text.New(pixel.ZV, infoAtlas()).AlignedTo(pixel.Center)
+	infoText.Color = colornames.Grey
+	infoText.Clear()
+	infoText.Write([]byte("Escape to Resume\n\nQ to Quit to Menu"))
+
+	return &PauseMenu{
+		titleText: titleText,
+		infoText:  infoText,
+		shade:     imdraw.New(nil),
+	}
+}
+
+// An overlay that stops the game below it from updating.
+type PauseMenu struct {
+	titleText *text.Text
+	infoText  *text.Text
+	shade     *imdraw.IMDraw
+}
+
+// DrawsBelow implements DrawsBelower.
+func (p *PauseMenu) DrawsBelow() bool {
+	return true
+}
+
+// Update implements Screen.
+func (p *PauseMenu) Update(win *pixelgl.Window, dt float64) SceneOp {
+	if win.JustPressed(pixelgl.KeyEscape) {
+		return Pop()
+	}
+	if win.JustPressed(pixelgl.KeyQ) {
+		return WithTransition(PopUntil(isMenu), Zoom(0.6, ent.EaseInOutCubic))
+	}
+	return nil
+}
+
+// Draw implements Screen.
+func (p *PauseMenu) Draw(win *pixelgl.Window) {
+	p.shade.Clear()
+	p.shade.Color = pixel.RGB(0, 0, 0).Mul(pixel.Alpha(0.6))
+	p.shade.Push(win.Bounds().Min, win.Bounds().Max)
+	p.shade.Rectangle(0)
+	p.shade.Draw(win)
+
+	p.titleText.Draw(win, pixel.IM.Moved(win.Bounds().Center()))
+	p.infoText.Draw(
+		win,
+		pixel.IM.Moved(
+			win.Bounds().Center(),
+		).Moved(
+			pixel.V(0, -p.titleText.LineHeight*2),
+		),
+	)
+}
@@ -3,6 +3,191 @@ package main
 import "github.com/gopxl/pixel/pixelgl"
 
 type Screen interface {
-	Update(win *pixelgl.Window, dt float64) Screen
+	// Update the screen, returning a change to make to the scene stack, or nil to stay as is.
+	Update(win *pixelgl.Window, dt float64) SceneOp
 	Draw(win *pixelgl.Window)
 }
+
+// Called when the screen is added to the stack.
+type Enterer interface {
+	Enter()
+}
+
+// Called when the screen is removed from the stack.
+type Exiter interface {
+	Exit()
+}
+
+// Called when another screen is pushed on top of this one.
+type Suspender interface {
+	Suspend()
+}
+
+// Called when this screen becomes the top screen again.
+type Resumer interface {
+	Resume()
+}
+
+// A screen that lets the screen below it keep drawing, such as an overlay.
+type DrawsBelower interface {
+	DrawsBelow() bool
+}
+
+// A screen that lets the screen below it keep updating.
+type UpdatesBelower interface {
+	UpdatesBelow() bool
+}
+
+// A change to make to the scene stack.
+type SceneOp func(*SceneStack)
+
+// Put the screen on top of the stack.
+func Push(s Screen) SceneOp {
+	return func(st *SceneStack) { st.Push(s) }
+}
+
+// Remove the top screen from the stack.
+func Pop() SceneOp {
+	return func(st *SceneStack) { st.Pop() }
+}
+
+// Remove screens from the top of the stack until the top one matches.
+// If none match, every screen above the bottom one is removed.
+func PopUntil(match func(Screen) bool) SceneOp {
+	return func(st *SceneStack) {
+		for len(st.screens) > 1 && !match(st.Top()) {
+			st.Pop()
+		}
+	}
+}
+
+// Swap the top screen of the stack for another.
+func Replace(s Screen) SceneOp {
+	return func(st *SceneStack) { st.Replace(s) }
+}
+
+// A stack of screens, where the top one is active and the ones below may be shown underneath it.
+type SceneStack struct {
+	screens    []Screen
+	transition *playingTransition
+	// Reused by each transition to hold the screens before and after it.
+	from, to *pixelgl.Canvas
+}
+
+func NewSceneStack(root Screen) *SceneStack {
+	st := &SceneStack{}
+	st.Push(root)
+	return st
+}
+
+// Get the top screen, or nil if the stack is empty.
+func (st *SceneStack) Top() Screen {
+	if len(st.screens) == 0 {
+		return nil
+	}
+	return st.screens[len(st.screens)-1]
+}
+
+// Are there no screens left?
+func (st *SceneStack) Empty() bool {
+	return len(st.screens) == 0
+}
+
+// Put the screen on top of the stack, suspending the previous top screen.
+func (st *SceneStack) Push(s Screen) {
+	if top, ok := st.Top().(Suspender); ok {
+		top.Suspend()
+	}
+	st.screens = append(st.screens, s)
+	if s, ok := s.(Enterer); ok {
+		s.Enter()
+	}
+}
+
+// Remove the top screen, resuming the one below it.
+func (st *SceneStack) Pop() {
+	if st.Empty() {
+		return
+	}
+	top := st.Top()
+	st.screens = st.screens[:len(st.screens)-1]
+	if top, ok := top.(Exiter); ok {
+		top.Exit()
+	}
+	if next, ok := st.Top().(Resumer); ok {
+		next.Resume()
+	}
+}
+
+// Swap the top screen for another, without suspending or resuming the screens below.
+func (st *SceneStack) Replace(s Screen) {
+	if !st.Empty() {
+		top := st.Top()
+		st.screens = st.screens[:len(st.screens)-1]
+		if top, ok := top.(Exiter); ok {
+			top.Exit()
+		}
+	}
+	st.screens = append(st.screens, s)
+	if s, ok := s.(Enterer); ok {
+		s.Enter()
+	}
+}
+
+// Get the index of the lowest screen that should be handled, given that each screen above it lets it through.
+func (st *SceneStack) lowest(letsThrough func(Screen) bool) int {
+	i := len(st.screens) - 1
+	for i > 0 && letsThrough(st.screens[i]) {
+		i--
+	}
+	return i
+}
+
+// Update the top screen, and any below it that it lets update, from the bottom up.
+// Then apply the change the top screen asked for. Changes asked for by the screens below are ignored,
+// as they are only being kept running, and the input they see is meant for the top screen.
+// While a transition is playing, it is moved forwards instead.
+func (st *SceneStack) Update(win *pixelgl.Window, dt float64) {
+	if st.transition != nil {
+		st.updateTransition(dt)
+		return
+	}
+	if st.Empty() {
+		return
+	}
+	top := st.Top()
+	var topOp SceneOp
+	for _, s := range st.screens[st.lowest(func(s Screen) bool {
+		u, ok := s.(UpdatesBelower)
+		return ok && u.UpdatesBelow()
+	}):] {
+		if op := s.Update(win, dt); s == top {
+			topOp = op
+		}
+	}
+	if topOp != nil {
+		topOp(st)
+	}
+}
+
+// Draw the top screen, and any below it that it lets draw, from the bottom up.
+// While a transition is playing, it is drawn instead.
+func (st *SceneStack) Draw(win *pixelgl.Window) {
+	if st.transition != nil {
+		st.drawTransition(win)
+		return
+	}
+	st.drawScreens(win)
+}
+
+func (st *SceneStack) drawScreens(win *pixelgl.Window) {
+	if st.Empty() {
+		return
+	}
+	for _, s := range st.screens[st.lowest(func(s Screen) bool {
+		d, ok := s.(DrawsBelower)
+		return ok && d.DrawsBelow()
+	}):] {
+		s.Draw(win)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"ent"
+	"image/color"
+
+	"github.com/gopxl/pixel"
+	"github.com/gopxl/pixel/imdraw"
+	"github.com/gopxl/pixel/pixelgl"
+)
+
+// Draws a transition part way through, given what the screens looked like before and after it.
+// t goes from 0 to 1, after easing.
+type TransitionStyle func(win *pixelgl.Window, from, to *pixelgl.Canvas, t float64)
+
+// An animated change from one set of screens to another.
+type Transition struct {
+	Duration float64
+	Easing   ent.Easing
+	Style    TransitionStyle
+}
+
+// Make the scene change play the transition.
+// While it plays, no screens are updated, so they do not receive input.
+func WithTransition(op SceneOp, t Transition) SceneOp {
+	return func(st *SceneStack) {
+		st.startTransition(op, t)
+	}
+}
+
+// Fade out to the color, then fade in to the new screens.
+func FadeTo(c color.Color, duration float64, easing ent.Easing) Transition {
+	overlay := imdraw.New(nil)
+	return Transition{duration, easing, func(win *pixelgl.Window, from, to *pixelgl.Canvas, t float64) {
+		shown, amount := from, t*2
+		if t >= 0.5 {
+			shown, amount = to, (1-t)*2
+		}
+		drawCanvas(win, shown, pixel.IM, pixel.Alpha(1))
+		overlay.Clear()
+		overlay.Color = pixel.ToRGBA(c).Mul(pixel.Alpha(amount))
+		overlay.Push(win.Bounds().Min, win.Bounds().Max)
+		overlay.Rectangle(0)
+		overlay.Draw(win)
+	}}
+}
+
+// Fade the new screens in over the old ones.
+func Crossfade(duration float64, easing ent.Easing) Transition {
+	return Transition{duration, easing, func(win *pixelgl.Window, from, to *pixelgl.Canvas, t float64) {
+		drawCanvas(win, from, pixel.IM, pixel.Alpha(1))
+		drawCanvas(win, to, pixel.IM, pixel.Alpha(t))
+	}}
+}
+
+// Reveal the new screens from left to right.
+func Wipe(duration float64, easing ent.Easing) Transition {
+	revealedSprite := pixel.NewSprite(nil, pixel.Rect{})
+	return Transition{duration, easing, func(win *pixelgl.Window, from, to *pixelgl.Canvas, t float64) {
+		drawCanvas(win, from, pixel.IM, pixel.Alpha(1))
+		bounds := to.Bounds()
+		revealed := pixel.R(bounds.Min.X, bounds.Min.Y, bounds.Min.X+bounds.W()*t, bounds.Max.Y)
+		if revealed.W() <= 0 {
+			return
+		}
+		revealedSprite.Set(to, revealed)
+		revealedSprite.Draw(win, pixel.IM.Moved(revealed.Center()))
+	}}
+}
+
+// Zoom into the old screens as they fade out, then zoom out of the new screens as they fade in.
+func Zoom(duration float64, easing ent.Easing) Transition {
+	return Transition{duration, easing, func(win *pixelgl.Window, from, to *pixelgl.Canvas, t float64) {
+		if t < 0.5 {
+			amount := t * 2
+			drawCanvas(win, from, pixel.IM.Scaled(pixel.ZV, 1+amount), pixel.Alpha(1-amount))
+		} else {
+			amount := (1 - t) * 2
+			drawCanvas(win, to, pixel.IM.Scaled(pixel.ZV, 1+amount), pixel.Alpha(1-amount))
+		}
+	}}
+}
+
+// Draw the canvas to fill the window, after transforming it about its center.
+func drawCanvas(win *pixelgl.Window, c *pixelgl.Canvas, m pixel.Matrix, mask pixel.RGBA) {
+	c.DrawColorMask(win, m.Moved(win.Bounds().Center()), mask)
+}
+
+// A transition waiting to start, or playing.
+// It waits for the screens before the change to be captured by a draw, then the change is made on the next update.
+type playingTransition struct {
+	Transition
+	op       SceneOp
+	captured bool
+	started  bool
+	elapsed  float64
+}
+
+// Is a transition waiting to start, or playing?
+// Nothing should change the stack directly while it is, as the change would happen part way through the transition.
+func (st *SceneStack) Transitioning() bool {
+	return st.transition != nil
+}
+
+func (st *SceneStack) startTransition(op SceneOp, t Transition) {
+	if st.transition != nil && !st.transition.started {
+		st.transition.op(st)
+	}
+	st.transition = &playingTransition{Transition: t, op: op}
+}
+
+// Make the change once the screens before it have been captured, then move the transition forwards, forgetting it once it has finished.
+func (st *SceneStack) updateTransition(dt float64) {
+	tr := st.transition
+	if !tr.started {
+		if tr.captured {
+			tr.op(st)
+			tr.started = true
+		}
+		return
+	}
+	tr.elapsed += dt
+	if tr.elapsed >= tr.Duration {
+		st.transition = nil
+	}
+}
+
+// Capture the screens before the change until it is made, then capture the screens after it every frame, and draw the transition between them.
+func (st *SceneStack) drawTransition(win *pixelgl.Window) {
+	tr := st.transition
+	if !tr.started {
+		st.drawScreens(win)
+		st.from = captureWindow(win, st.from)
+		tr.captured = true
+		return
+	}
+	st.drawScreens(win)
+	st.to = captureWindow(win, st.to)
+	win.Clear(color.Black)
+	t := 1.0
+	if tr.Duration > 0 {
+		t = min(tr.elapsed/tr.Duration, 1)
+	}
+	if tr.Easing != nil {
+		t = tr.Easing(t)
+	}
+	tr.Style(win, st.from, st.to, t)
+}
+
+// Copy what has been drawn to the window onto the canvas, creating or resizing the canvas to fit the window if needed.
+func captureWindow(win *pixelgl.Window, c *pixelgl.Canvas) *pixelgl.Canvas {
+	if c == nil {
+		c = pixelgl.NewCanvas(win.Bounds())
+	} else if c.Bounds() != win.Bounds() {
+		c.SetBounds(win.Bounds())
+	}
+	c.Clear(color.Transparent)
+	win.Canvas().Draw(c, pixel.IM.Moved(c.Bounds().Center()))
+	return c
+}